@@ -0,0 +1,41 @@
+package radix
+
+// BatchRoute is a route registered by AddBatch.
+type BatchRoute struct {
+	Path    []string
+	Handler Handler
+}
+
+// AddBatch registers every route in routes, or none of them. The routes are
+// added to a copy of the tree that is only published once all of them
+// succeed; otherwise the first error is returned and the tree is unchanged.
+func (r *RadixTree) AddBatch(routes []BatchRoute) error {
+	return r.update(func(root *Node) error {
+		for _, route := range routes {
+			if _, err := r.addRoute(root, route.Path, assignHandler(route.Handler), nil); err != nil {
+				return err
+			}
+		}
+		for _, route := range routes {
+			r.record(journalEntry{Op: journalAdd, Path: route.Path})
+		}
+		return nil
+	})
+}
+
+// DeleteBatch deletes the route at each of paths like Delete and returns
+// one error per path, in the same order, nil where the deletion succeeded.
+// Every path is attempted whatever the others' outcome, and the successful
+// deletions are published together as one change.
+func (r *RadixTree) DeleteBatch(paths [][]string) []error {
+	errs := make([]error, len(paths))
+	r.update(func(root *Node) error {
+		for i, path := range paths {
+			if errs[i] = r.deleteRoute(root, "", path); errs[i] == nil {
+				r.record(journalEntry{Op: journalDelete, Path: path})
+			}
+		}
+		return nil
+	})
+	return errs
+}
@@ -0,0 +1,81 @@
+package radix_test
+
+import (
+	"strings"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddBatch(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	err := tree.AddBatch([]radix.BatchRoute{
+		{Path: []string{"users"}, Handler: "users"},
+		{Path: []string{"users", ":id"}, Handler: "user_show"},
+		{Path: []string{"files", "*filepath"}, Handler: "files"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), tree.Size())
+
+	routes := tree.Get([]string{"users", "42"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "user_show", routes[0].Handler.(string))
+}
+
+func TestAddBatchIsAtomic(t *testing.T) {
+	tree := radix.NewRadixTree().WithJournal()
+	tree.Add([]string{"users", ":id"}, "user_show")
+
+	err := tree.AddBatch([]radix.BatchRoute{
+		{Path: []string{"posts"}, Handler: "posts"},
+		{Path: []string{"posts", ":id"}, Handler: "post_show"},
+		{Path: []string{"users", ":id"}, Handler: "conflict"},
+		{Path: []string{"comments"}, Handler: "comments"},
+	})
+	assert.ErrorIs(t, err, radix.ErrHandlerExists)
+	assert.Equal(t, uint32(1), tree.Size(), "Failed batch should not change the size")
+	assert.Len(t, tree.Get([]string{"posts"}), 0, "Routes before the conflict should be rolled back")
+	assert.Len(t, tree.Get([]string{"comments"}), 0)
+	assert.Equal(t, map[string]radix.Handler{"/users/:id": "user_show"}, routeTable(tree))
+
+	var journal strings.Builder
+	assert.Nil(t, tree.WriteJournal(&journal))
+	assert.Equal(t, 1, strings.Count(journal.String(), "\n"), "Failed batch should not be journaled")
+
+	routes := tree.Get([]string{"users", "42"})
+	assert.Equal(t, "user_show", routes[0].Handler.(string))
+}
+
+func TestDeleteBatch(t *testing.T) {
+	tree := radix.NewRadixTree().WithJournal()
+	tree.Add([]string{"users"}, "users")
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"files", "*filepath"}, "files")
+	tree.Add([]string{"health"}, "health")
+
+	errs := tree.DeleteBatch([][]string{
+		{"users", ":id"},
+		{"missing"},
+		{"files", "*filepath"},
+		{"users", ":id"},
+	})
+	assert.Len(t, errs, 4)
+	assert.Nil(t, errs[0])
+	assert.ErrorIs(t, errs[1], radix.ErrPathNotFound)
+	assert.Nil(t, errs[2], "Later paths should be attempted after a failure")
+	assert.ErrorIs(t, errs[3], radix.ErrPathNotFound, "A path deleted earlier in the batch is gone")
+
+	assert.Equal(t, uint32(2), tree.Size())
+	assert.Equal(t, tree.Count(), int(tree.Size()))
+	assert.Nil(t, tree.Get([]string{"users", "42"}))
+	assert.Nil(t, tree.Get([]string{"files", "a"}))
+	assert.Len(t, tree.Get([]string{"users"}), 1)
+
+	var journal strings.Builder
+	assert.Nil(t, tree.WriteJournal(&journal))
+	assert.Equal(t, 2, strings.Count(journal.String(), `"op":"delete"`), "Only successful deletions are journaled")
+
+	assert.Empty(t, tree.DeleteBatch(nil))
+}
@@ -0,0 +1,59 @@
+package radix
+
+import "fmt"
+
+// RouteBuilder assembles a route segment by segment, adding the param and
+// wildcard markers of the tree's syntax. It is created by RadixTree.Route
+// and registers the route when Handler is called.
+type RouteBuilder struct {
+	tree     *RadixTree
+	segments []string
+	err      error
+}
+
+// Route starts building a route on the tree.
+func (r *RadixTree) Route() *RouteBuilder {
+	return &RouteBuilder{tree: r}
+}
+
+// Static appends a literal segment. Segments that would read as a param or
+// wildcard are escaped.
+func (b *RouteBuilder) Static(segment string) *RouteBuilder {
+	return b.append(b.tree.escape(segment))
+}
+
+// Param appends a param segment capturing its value under name.
+func (b *RouteBuilder) Param(name string) *RouteBuilder {
+	return b.append(b.tree.paramSegment(name))
+}
+
+// Wildcard appends a catch-all segment capturing the rest of the path under
+// name. It must be the last segment of the route.
+func (b *RouteBuilder) Wildcard(name string) *RouteBuilder {
+	return b.append(b.tree.wildcardSegment(name))
+}
+
+func (b *RouteBuilder) append(segment string) *RouteBuilder {
+	if b.err == nil && len(b.segments) > 0 {
+		if last := b.segments[len(b.segments)-1]; b.tree.isWildcard(last) {
+			b.err = fmt.Errorf("%w: %q", ErrWildcardNotLast, last)
+		}
+	}
+	b.segments = append(b.segments, segment)
+	return b
+}
+
+// Handler registers the built route with Add and returns the first error
+// found while building or adding it.
+func (b *RouteBuilder) Handler(h Handler) error {
+	if b.err != nil {
+		return b.err
+	}
+	_, err := b.tree.Add(b.segments, h)
+	return err
+}
+
+func (r *RadixTree) isWildcard(segment string) bool {
+	nodeType, _ := r.classify(segment)
+	return nodeType == Wildcard
+}
@@ -0,0 +1,51 @@
+package radix_test
+
+import (
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteBuilder(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	err := tree.Route().Static("api").Static("v1").Param("id").Wildcard("rest").Handler("handler")
+	assert.Nil(t, err)
+
+	routes := tree.Get([]string{"api", "v1", "42", "a", "b"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{
+		{Key: "id", Values: []string{"42"}},
+		{Key: "rest", Values: []string{"a", "b"}},
+	}, routes[0].Params)
+
+	assert.Nil(t, tree.Route().Handler("root"))
+	assert.Len(t, tree.Get([]string{}), 1)
+
+	assert.Nil(t, tree.Route().Static("config").Static(":443").Handler("port"))
+	routes = tree.Get([]string{"config", ":443"})
+	assert.Len(t, routes, 1)
+	assert.Empty(t, routes[0].Params, "Static segments should be escaped")
+
+	err = tree.Route().Static("config").Static(":443").Handler("again")
+	assert.ErrorIs(t, err, radix.ErrHandlerExists)
+}
+
+func TestRouteBuilderWildcardNotLast(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	err := tree.Route().Static("files").Wildcard("path").Static("meta").Handler("handler")
+	assert.ErrorIs(t, err, radix.ErrWildcardNotLast)
+	assert.Zero(t, tree.Size())
+}
+
+func TestRouteBuilderCustomMarkers(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{
+		ParamPrefix: '{', ParamSuffix: '}',
+		WildcardPrefix: '[', WildcardSuffix: ']',
+	})
+
+	assert.Nil(t, tree.Route().Static("users").Param("id").Wildcard("rest").Handler("handler"))
+	assert.Nil(t, tree.Delete([]string{"users", "{id}", "[rest]"}))
+}
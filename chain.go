@@ -0,0 +1,50 @@
+package radix
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Chain is the handler stored by AddChain: a list of handlers run in order,
+// such as a middleware pipeline ending in the endpoint.
+type Chain []Handler
+
+// AddChain appends handlers to the chain registered at path, creating the
+// route if needed. Unlike Add, registering a chain on the same path again
+// extends it rather than conflicting; the route still counts once in Size.
+// A path holding a plain handler from Add returns ErrHandlerExists. Delete
+// removes the whole chain.
+func (r *RadixTree) AddChain(path []string, handlers ...Handler) error {
+	if len(handlers) == 0 {
+		return fmt.Errorf("chain needs at least one handler")
+	}
+	return r.update(func(root *Node) error {
+		if node := r.findNode(root, path); node != nil {
+			if chain, ok := node.handler.(Chain); ok {
+				trail := r.ownTrail(root, path)
+				trail[len(trail)-1].handler = slices.Concat(chain, handlers)
+				r.record(journalEntry{Op: journalChain, Path: path})
+				return nil
+			}
+		}
+		if _, err := r.addRoute(root, path, assignHandler(Chain(slices.Clone(handlers))), nil); err != nil {
+			return err
+		}
+		r.record(journalEntry{Op: journalChain, Path: path})
+		return nil
+	})
+}
+
+// GetChain returns the handlers of the highest-priority route matching path
+// in registration order, with the params it captured. A route registered
+// with Add yields a single handler.
+func (r *RadixTree) GetChain(path []string) ([]Handler, Params, bool) {
+	routes := r.GetN(path, 1)
+	if len(routes) == 0 {
+		return nil, nil, false
+	}
+	if chain, ok := routes[0].Handler.(Chain); ok {
+		return chain, routes[0].Params, true
+	}
+	return []Handler{routes[0].Handler}, routes[0].Params, true
+}
@@ -0,0 +1,60 @@
+package radix_test
+
+import (
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddChain(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	assert.Nil(t, tree.AddChain([]string{"users", ":id"}, "auth", "logging"))
+	before := tree.Clone()
+	assert.Nil(t, tree.AddChain([]string{"users", ":id"}, "user_show"))
+	assert.Equal(t, uint32(1), tree.Size(), "Extending a chain should not add a route")
+
+	handlers, _, _ := before.GetChain([]string{"users", "42"})
+	assert.Equal(t, []radix.Handler{"auth", "logging"}, handlers, "Extending a chain should leave clones alone")
+
+	handlers, params, found := tree.GetChain([]string{"users", "42"})
+	assert.True(t, found)
+	assert.Equal(t, []radix.Handler{"auth", "logging", "user_show"}, handlers)
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, params)
+
+	_, _, found = tree.GetChain([]string{"posts"})
+	assert.False(t, found)
+}
+
+func TestAddChainConflicts(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"users"}, "users")
+	assert.ErrorIs(t, tree.AddChain([]string{"users"}, "auth"), radix.ErrHandlerExists)
+	assert.NotNil(t, tree.AddChain([]string{"posts"}), "Empty chains should be rejected")
+
+	handlers, _, found := tree.GetChain([]string{"users"})
+	assert.True(t, found)
+	assert.Equal(t, []radix.Handler{"users"}, handlers, "Plain handlers form a chain of one")
+
+	tree.AddChain([]string{"posts"}, "auth", "posts")
+	_, err := tree.Add([]string{"posts"}, "posts")
+	assert.ErrorIs(t, err, radix.ErrHandlerExists)
+}
+
+func TestDeleteChain(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.AddChain([]string{"files", "*filepath"}, "auth")
+	tree.AddChain([]string{"files", "*filepath"}, "files")
+	before, _, _ := tree.GetChain([]string{"files", "a"})
+
+	assert.Nil(t, tree.Delete([]string{"files", "*filepath"}))
+	_, _, found := tree.GetChain([]string{"files", "a"})
+	assert.False(t, found, "Delete should remove the whole chain")
+	assert.Zero(t, tree.Size())
+
+	tree.AddChain([]string{"files", "*filepath"}, "other")
+	assert.Equal(t, []radix.Handler{"auth", "files"}, before, "Earlier results should not be modified")
+}
@@ -0,0 +1,50 @@
+package radix
+
+// Compact removes every node that holds no handler and has no children,
+// repeating up the tree as parents become empty, and returns how many nodes
+// it removed. Writes already prune the nodes they empty; Compact is a
+// safety net for structure left behind regardless. Like Delete, it is
+// applied to a copy of the tree.
+func (r *RadixTree) Compact() int {
+	removed := 0
+	r.update(func(root *Node) error {
+		if removed = r.compact(root); removed > 0 {
+			r.record(journalEntry{Op: journalCompact})
+		}
+		return nil
+	})
+	return removed
+}
+
+// compact removes the dead nodes below node, which must be owned by the
+// write in progress, in post-order. Only the branches holding dead nodes
+// are copied.
+func (r *RadixTree) compact(node *Node) int {
+	removed := 0
+	for _, child := range node.sortedChildren() {
+		if !child.hasDeadLeaf() {
+			continue
+		}
+		child = r.ownChild(node, child)
+		removed += r.compact(child)
+		if child.handler == nil && len(child.methods) == 0 && !child.hasChildren() {
+			node.removeChild(child)
+			removed++
+		}
+	}
+	return removed
+}
+
+// hasDeadLeaf reports whether node or a node below it holds no handler and
+// has no children, the nodes compaction starts from.
+func (n *Node) hasDeadLeaf() bool {
+	if !n.hasChildren() {
+		return n.handler == nil && len(n.methods) == 0
+	}
+	for _, child := range n.sortedChildren() {
+		if child.hasDeadLeaf() {
+			return true
+		}
+	}
+	return false
+}
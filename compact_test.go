@@ -0,0 +1,61 @@
+package radix_test
+
+import (
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompact(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id"}, "user")
+	tree.Add([]string{"files", "*path"}, "files")
+
+	assert.Nil(t, tree.AddDanglingForTest([]string{"users", ":id", "posts", ":post"}))
+	assert.Nil(t, tree.AddDanglingForTest([]string{"stale", "branch"}))
+	assert.Nil(t, tree.AddDanglingForTest([]string{"files", "*other"}))
+	assert.Equal(t, 10, tree.Stats().TotalNodes)
+
+	assert.Equal(t, 5, tree.Compact())
+	assert.Equal(t, radix.TreeStats{
+		MaxDepth:       2,
+		TotalNodes:     5,
+		LeafRoutes:     2,
+		ParamNodes:     1,
+		WildcardNodes:  1,
+		StaticNodes:    2,
+		LatestSequence: 2,
+	}, tree.Stats())
+	assert.Equal(t, uint32(2), tree.Size())
+	assert.Equal(t, []string{"user"}, handlers(tree.Get([]string{"users", "42"})))
+	assert.Equal(t, []string{"files"}, handlers(tree.Get([]string{"files", "a", "b"})))
+
+	assert.Equal(t, 0, tree.Compact(), "A compact tree should be left alone")
+}
+
+func TestCompactLeavesEarlierVersionsAlone(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id"}, "user")
+	tree.Add([]string{"files", "*path"}, "files")
+	assert.Nil(t, tree.AddDanglingForTest([]string{"users", ":id", "posts"}))
+	before := tree.Clone()
+
+	assert.Equal(t, 1, tree.Compact())
+	assert.Equal(t, 6, before.Stats().TotalNodes, "Compact should copy the nodes it prunes")
+	assert.False(t, tree.SameNodeForTest(before, []string{"users", ":id"}))
+	assert.True(t, tree.SameNodeForTest(before, []string{"files"}), "Branches without dead nodes should be shared")
+}
+
+func TestCompactAfterDeletes(t *testing.T) {
+	tree := radix.NewRadixTree()
+	for _, path := range [][]string{{"a", "b", "c"}, {"a", ":x", "d"}, {"e", "*rest"}} {
+		tree.Add(path, "handler")
+		tree.AddMethod("GET", path, "get")
+	}
+	for _, path := range [][]string{{"a", "b", "c"}, {"a", ":x", "d"}, {"e", "*rest"}} {
+		tree.Delete(path)
+	}
+	assert.Equal(t, 0, tree.Compact(), "Nodes still holding methods are not dead")
+	assert.Equal(t, uint32(3), tree.Size())
+}
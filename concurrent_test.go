@@ -0,0 +1,262 @@
+package radix_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+)
+
+func TestConcurrentDelete(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	count := 200
+	for i := range count {
+		tree.Add([]string{"users", fmt.Sprintf("u%d", i), "posts", ":post_id"}, fmt.Sprintf("handler%d", i))
+	}
+	tree.Add([]string{"users", "*rest"}, "catch_all")
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				for i := range count {
+					path := []string{"users", fmt.Sprintf("u%d", i), "posts", "42"}
+					routes := tree.Get(path)
+					// The catch-all always matches; the exact route is either
+					// fully present (with its params) or gone.
+					if len(routes) != 1 && len(routes) != 2 {
+						t.Errorf("Unexpected number of routes %d for %v", len(routes), path)
+						return
+					}
+					if len(routes) == 2 {
+						route := routes[0]
+						if route.Handler.(string) != fmt.Sprintf("handler%d", i) {
+							t.Errorf("Unexpected handler %v for %v", route.Handler, path)
+							return
+						}
+						if values, ok := route.Params.Get("post_id"); !ok || values[0] != "42" {
+							t.Errorf("Unexpected params %v for %v", route.Params, path)
+							return
+						}
+					}
+					if routes[len(routes)-1].Handler.(string) != "catch_all" {
+						t.Errorf("Catch-all route missing for %v", path)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	for i := range count {
+		if err := tree.Delete([]string{"users", fmt.Sprintf("u%d", i), "posts", ":post_id"}); err != nil {
+			t.Errorf("Unexpected error deleting route %d: %v", i, err)
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	if tree.Size() != 1 {
+		t.Errorf("Expected only the catch-all route to remain, got size %d", tree.Size())
+	}
+}
+
+// TestRaceHeavy mixes every kind of write with lock-free lookups; run it
+// with -race. Readers must always see either the whole of a route or none
+// of it.
+func TestRaceHeavy(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"static"}, "static")
+
+	const writers, readers, rounds = 4, 8, 200
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	for range readers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if routes := tree.Get([]string{"static"}); len(routes) != 1 {
+					t.Errorf("Static route should always be visible, got %d routes", len(routes))
+					return
+				}
+				for w := range writers {
+					path := []string{fmt.Sprintf("w%d", w), "items", "42"}
+					for _, route := range tree.Get(path) {
+						if values, ok := route.Params.Get("id"); !ok || values[0] != "42" {
+							t.Errorf("Unexpected params %v for %v", route.Params, path)
+							return
+						}
+					}
+					tree.GetMethod("GET", path)
+					tree.Walk(func(path []string, handler radix.Handler) bool { return true })
+					size := tree.Size()
+					if size == 0 {
+						t.Errorf("Size should never drop below the static route")
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	var writersWg sync.WaitGroup
+	for w := range writers {
+		writersWg.Add(1)
+		go func() {
+			defer writersWg.Done()
+			prefix := fmt.Sprintf("w%d", w)
+			for i := range rounds {
+				path := []string{prefix, "items", ":id"}
+				if _, err := tree.Add(path, i); err != nil {
+					t.Errorf("Unexpected error adding %v: %v", path, err)
+				}
+				if err := tree.AddMethod("GET", path, i); err != nil {
+					t.Errorf("Unexpected error adding GET %v: %v", path, err)
+				}
+				tree.AddFallthrough([]string{prefix, "*rest"}, i)
+				if err := tree.Delete(path); err != nil {
+					t.Errorf("Unexpected error deleting %v: %v", path, err)
+				}
+				if _, err := tree.DeleteSubtree([]string{prefix}); err != nil {
+					t.Errorf("Unexpected error deleting %v: %v", prefix, err)
+				}
+			}
+		}()
+	}
+
+	writersWg.Wait()
+	close(done)
+	wg.Wait()
+
+	if tree.Size() != 1 || tree.Count() != 1 {
+		t.Errorf("Expected only the static route to remain, got size %d and count %d", tree.Size(), tree.Count())
+	}
+}
+
+// TestClonesWriteConcurrently checks that a tree and its clone, which share
+// nodes, can be written and read at once; run it with -race.
+func TestClonesWriteConcurrently(t *testing.T) {
+	tree := radix.NewRadixTree()
+	for i := range 50 {
+		tree.Add([]string{"shared", fmt.Sprint(i)}, i)
+	}
+	trees := []*radix.RadixTree{tree, tree.Clone()}
+
+	var wg sync.WaitGroup
+	for n, tree := range trees {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				path := []string{"shared", fmt.Sprint(i % 50), fmt.Sprint(n)}
+				tree.Add(path, i)
+				tree.Delete(path)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				if routes := tree.Get([]string{"shared", fmt.Sprint(i % 50)}); len(routes) != 1 {
+					t.Errorf("Shared route should stay visible, got %d routes", len(routes))
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, tree := range trees {
+		if tree.Size() != 50 {
+			t.Errorf("Expected the shared routes to remain, got size %d", tree.Size())
+		}
+	}
+}
+
+// TestDeleteKeepsReadersConsistent checks that a deleted route disappears
+// from lookups at once, while readers already holding a node keep a
+// consistent view of it; run it with -race. Deletes publish a new copy of
+// the tree, so nodes a reader holds are never modified.
+func TestDeleteKeepsReadersConsistent(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	const count = 200
+	path := func(i int) []string { return []string{"items", fmt.Sprintf("i%d", i), ":id"} }
+	for i := range count {
+		tree.Add(path(i), i)
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				for i := range count {
+					leaf, params, found := tree.GetLeaf([]string{"items", fmt.Sprintf("i%d", i), "42"})
+					if !found {
+						continue
+					}
+					if handler, ok := leaf.Handler(); !ok || handler.(int) != i {
+						t.Errorf("Unexpected handler %v for route %d", handler, i)
+						return
+					}
+					if values, ok := params.Get("id"); !ok || values[0] != "42" {
+						t.Errorf("Unexpected params %v for route %d", params, i)
+						return
+					}
+					if parent, ok := leaf.Parent(); !ok || parent.PathName() != fmt.Sprintf("i%d", i) {
+						t.Errorf("Detached node lost its parent for route %d", i)
+						return
+					}
+				}
+				tree.Walk(func(path []string, handler radix.Handler) bool {
+					if handler == nil {
+						t.Errorf("Walk reported %v without a handler", path)
+					}
+					return true
+				})
+			}
+		}()
+	}
+
+	for i := range count {
+		if err := tree.Delete(path(i)); err != nil {
+			t.Errorf("Unexpected error deleting route %d: %v", i, err)
+		}
+		if routes := tree.Get([]string{"items", fmt.Sprintf("i%d", i), "42"}); len(routes) != 0 {
+			t.Errorf("Deleted route %d is still reachable", i)
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	if tree.Size() != 0 {
+		t.Errorf("Expected an empty tree, got size %d", tree.Size())
+	}
+}
@@ -0,0 +1,226 @@
+package radix
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"slices"
+)
+
+// FindConflicts reports every pair of registered routes that some concrete
+// path matches at once, such as "/api/:version" and "/api/*path". Routes are
+// given as Walk reports them, in the order of a sorted traversal. Param
+// constraints are honoured against static segments, but two params are
+// assumed to overlap whatever their constraints. Handlers registered for
+// different methods on the same path are not reported.
+func (r *RadixTree) FindConflicts() [][2][]string {
+	var leaves [][]*Node
+	var collect func(node *Node, lineage []*Node)
+	collect = func(node *Node, lineage []*Node) {
+		if node.handler != nil || len(node.methods) > 0 {
+			leaves = append(leaves, lineage)
+		}
+		for _, child := range node.sortedChildren() {
+			collect(child, append(lineage[:len(lineage):len(lineage)], child))
+		}
+	}
+	collect(r.root.Load(), nil)
+
+	conflicts := [][2][]string{}
+	for i := range leaves {
+		for j := i + 1; j < len(leaves); j++ {
+			if overlaps(leaves[i], leaves[j]) {
+				conflicts = append(conflicts, [2][]string{r.segments(leaves[i]), r.segments(leaves[j])})
+			}
+		}
+	}
+	return conflicts
+}
+
+// overlaps reports whether some path is matched by both patterns a and b.
+func overlaps(a, b []*Node) bool {
+	switch {
+	case len(a) > 0 && a[0].nodeType == Wildcard:
+		return len(b) >= a[0].minCapture() || len(b) > 0 && b[len(b)-1].nodeType == Wildcard
+	case len(b) > 0 && b[0].nodeType == Wildcard:
+		return len(a) >= b[0].minCapture() || len(a) > 0 && a[len(a)-1].nodeType == Wildcard
+	case len(a) == 0 || len(b) == 0:
+		return len(a) == len(b)
+	}
+
+	x, y := a[0], b[0]
+	if x.nodeType == ParamNode && y.nodeType == Static {
+		x, y = y, x
+	}
+	switch {
+	case x.nodeType == Static && y.nodeType == Static:
+		if x.path != y.path {
+			return false
+		}
+	case x.nodeType == Static:
+		if y.constraint != nil && !y.constraint.MatchString(x.path) {
+			return false
+		}
+	}
+	return overlaps(a[1:], b[1:])
+}
+
+// segments returns the route segments of a lineage as Walk reports them.
+func (r *RadixTree) segments(lineage []*Node) []string {
+	segments := make([]string, len(lineage))
+	for i, node := range lineage {
+		segments[i] = r.label(node)
+	}
+	return segments
+}
+
+// UnreachableRoutes reports routes that can never be the first match for any
+// path, because a higher-priority sibling route matches every path they do.
+// Two cases are detected: a wildcard registered after a sibling wildcard
+// that matches at least the same paths, and a param whose constraint only
+// accepts a fixed set of values that all have a static sibling holding the
+// same remaining route. Routes are given as Walk reports them, in a stable
+// order.
+func (r *RadixTree) UnreachableRoutes() [][]string {
+	unreachable := [][]string{}
+	root := r.root.Load()
+	var visit func(node *Node, lineage []*Node)
+	visit = func(node *Node, lineage []*Node) {
+		if (node.handler != nil || len(node.methods) > 0) && shadowed(root, lineage) {
+			unreachable = append(unreachable, r.segments(lineage))
+		}
+		for _, child := range node.sortedChildren() {
+			visit(child, append(lineage[:len(lineage):len(lineage)], child))
+		}
+	}
+	visit(root, nil)
+	return unreachable
+}
+
+// shadowed reports whether the route at the end of lineage, which starts
+// below root, is always preceded by a sibling route.
+func shadowed(root *Node, lineage []*Node) bool {
+	for i, node := range lineage {
+		parent := root
+		if i > 0 {
+			parent = lineage[i-1]
+		}
+		switch node.nodeType {
+		case Wildcard:
+			siblings := parent.wildcard_children
+			if slices.ContainsFunc(siblings[:slices.Index(siblings, node)], func(earlier *Node) bool {
+				return earlier.minCapture() <= node.minCapture()
+			}) {
+				return true
+			}
+		case ParamNode:
+			values, ok := literalValues(node.constraint)
+			if ok && !slices.ContainsFunc(values, func(value string) bool {
+				return !hasRoute(parent.static_children[value], lineage[i+1:])
+			}) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasRoute reports whether a route with the pattern of rest is registered
+// below node.
+func hasRoute(node *Node, rest []*Node) bool {
+	for _, next := range rest {
+		if node == nil {
+			return false
+		}
+		switch next.nodeType {
+		case Static:
+			node = node.static_children[next.path]
+		case ParamNode:
+			node = node.params_children[next.paramName]
+		default:
+			i := slices.IndexFunc(node.wildcard_children, func(wc *Node) bool { return wc.path == next.path })
+			if i < 0 {
+				return false
+			}
+			node = node.wildcard_children[i]
+		}
+	}
+	return node != nil && (node.handler != nil || len(node.methods) > 0)
+}
+
+// literalValues returns the finite set of segments accepted by an anchored
+// constraint such as `^(new|edit)$`.
+func literalValues(re *regexp.Regexp) ([]string, bool) {
+	if re == nil {
+		return nil, false
+	}
+	parsed, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return nil, false
+	}
+	parsed = parsed.Simplify()
+	if parsed.Op != syntax.OpConcat || len(parsed.Sub) < 2 ||
+		parsed.Sub[0].Op != syntax.OpBeginText || parsed.Sub[len(parsed.Sub)-1].Op != syntax.OpEndText {
+		return nil, false
+	}
+	return language(&syntax.Regexp{Op: syntax.OpConcat, Sub: parsed.Sub[1 : len(parsed.Sub)-1]})
+}
+
+// maxLanguage bounds the number of strings language enumerates.
+const maxLanguage = 64
+
+// language enumerates the strings matched by re when it matches a small
+// finite set of them.
+func language(re *syntax.Regexp) ([]string, bool) {
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		return []string{""}, true
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			return nil, false
+		}
+		return []string{string(re.Rune)}, true
+	case syntax.OpCapture:
+		return language(re.Sub[0])
+	case syntax.OpCharClass:
+		var values []string
+		for i := 0; i < len(re.Rune); i += 2 {
+			for c := re.Rune[i]; c <= re.Rune[i+1]; c++ {
+				if values = append(values, string(c)); len(values) > maxLanguage {
+					return nil, false
+				}
+			}
+		}
+		return values, true
+	case syntax.OpAlternate:
+		var values []string
+		for _, sub := range re.Sub {
+			subValues, ok := language(sub)
+			if !ok {
+				return nil, false
+			}
+			if values = append(values, subValues...); len(values) > maxLanguage {
+				return nil, false
+			}
+		}
+		return values, true
+	case syntax.OpConcat:
+		values := []string{""}
+		for _, sub := range re.Sub {
+			subValues, ok := language(sub)
+			if !ok {
+				return nil, false
+			}
+			var next []string
+			for _, prefix := range values {
+				for _, suffix := range subValues {
+					next = append(next, prefix+suffix)
+				}
+			}
+			if values = next; len(values) > maxLanguage {
+				return nil, false
+			}
+		}
+		return values, true
+	}
+	return nil, false
+}
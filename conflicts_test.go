@@ -0,0 +1,95 @@
+package radix_test
+
+import (
+	"regexp"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindConflicts(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"api", ":version"}, "api_version")
+	tree.Add([]string{"api", "*path"}, "api_catch_all")
+	tree.Add([]string{"users", "new"}, "user_new")
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"users", ":id", "posts"}, "user_posts")
+	tree.Add([]string{"files", "readme"}, "readme")
+	tree.AddMethod("GET", []string{"files", "readme"}, "readme_get")
+
+	expected := [][2][]string{
+		{{"api", ":version"}, {"api", "*path"}},
+		{{"users", "new"}, {"users", ":id"}},
+	}
+	assert.Equal(t, expected, tree.FindConflicts())
+}
+
+func TestFindConflictsNone(t *testing.T) {
+	tree := radix.NewRadixTree()
+	assert.Empty(t, tree.FindConflicts())
+
+	tree.Add([]string{}, "root")
+	tree.Add([]string{"users"}, "users")
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"files", "*filepath"}, "files")
+	tree.AddWithConstraints([]string{"posts", ":id"}, "post_show", map[string]*regexp.Regexp{"id": regexp.MustCompile(`^\d+$`)})
+	tree.Add([]string{"posts", "latest"}, "post_latest")
+	assert.Empty(t, tree.FindConflicts(), "Different lengths and rejected constraints never overlap")
+}
+
+func TestFindConflictsWildcards(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"files"}, "files_root")
+	tree.Add([]string{"files", "**filepath"}, "files")
+	tree.Add([]string{"*rest"}, "rest")
+
+	expected := [][2][]string{
+		{{"files"}, {"files", "**filepath"}},
+		{{"files"}, {"*rest"}},
+		{{"files", "**filepath"}, {"*rest"}},
+	}
+	assert.Equal(t, expected, tree.FindConflicts())
+}
+
+func TestUnreachableRoutes(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"files", "*filepath"}, "files")
+	tree.Add([]string{"files", "*other"}, "files_other")
+	tree.Add([]string{"files", "*filepath"}, "files_again")
+	tree.Add([]string{"docs", "*page"}, "docs")
+	tree.Add([]string{"docs", "**all"}, "docs_all")
+
+	action := map[string]*regexp.Regexp{"action": regexp.MustCompile(`^(new|edit)$`)}
+	tree.Add([]string{"users", "new"}, "user_new")
+	tree.Add([]string{"users", "edit"}, "user_edit")
+	tree.AddWithConstraints([]string{"users", ":action"}, "user_action", action)
+	tree.Add([]string{"users", "new", "preview"}, "user_new_preview")
+	tree.AddWithConstraints([]string{"users", ":action", "preview"}, "user_action_preview", action)
+	tree.AddWithConstraints([]string{"users", ":action", "history"}, "user_action_history", action)
+
+	tree.AddWithConstraints([]string{"posts", ":action"}, "post_action", map[string]*regexp.Regexp{"action": regexp.MustCompile(`^(new|edit)$`)})
+	tree.Add([]string{"posts", "new"}, "post_new")
+
+	expected := [][]string{
+		{"files", "*other"},
+		{"files", "*filepath"},
+		{"users", ":action"},
+	}
+	assert.Equal(t, expected, tree.UnreachableRoutes())
+}
+
+func TestUnreachableRoutesNone(t *testing.T) {
+	tree := radix.NewRadixTree()
+	assert.Empty(t, tree.UnreachableRoutes())
+
+	tree.Add([]string{"users", "new"}, "user_new")
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"users", "*rest"}, "user_rest")
+	tree.AddWithConstraints([]string{"posts", ":id"}, "post_show", map[string]*regexp.Regexp{"id": regexp.MustCompile(`^\d+$`)})
+	tree.Add([]string{"posts", "1"}, "post_one")
+	assert.Empty(t, tree.UnreachableRoutes(), "Open-ended params and single wildcards are reachable")
+}
@@ -0,0 +1,60 @@
+package radix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var dotColors = map[NodeType]string{
+	Static:    "black",
+	ParamNode: "blue",
+	Wildcard:  "red",
+}
+
+// ToDOT renders the tree as a Graphviz digraph. Nodes are labeled with their
+// segment and colored by type (static black, param blue, wildcard red);
+// nodes holding a handler are drawn as double circles.
+func (r *RadixTree) ToDOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph radix {\n")
+	id := 0
+	writeDOTNode(&sb, r.root.Load(), &id)
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func writeDOTNode(sb *strings.Builder, node *Node, id *int) int {
+	nodeID := *id
+	*id++
+
+	label := node.path
+	if nodeID == 0 {
+		label = "<root>"
+	}
+	shape := "circle"
+	if node.handler != nil || len(node.methods) > 0 {
+		shape = "doublecircle"
+	}
+	fmt.Fprintf(sb, "\tn%d [label=%s, shape=%s, color=%s];\n", nodeID, strconv.Quote(label), shape, dotColors[node.nodeType])
+
+	for _, child := range node.sortedChildren() {
+		childID := writeDOTNode(sb, child, id)
+		fmt.Fprintf(sb, "\tn%d -> n%d;\n", nodeID, childID)
+	}
+	return nodeID
+}
+
+// sortedChildren returns the children of n in a stable order: static
+// children by segment, param children by name, then wildcards in
+// registration order.
+func (n *Node) sortedChildren() []*Node {
+	children := make([]*Node, 0, len(n.static_children)+len(n.params_children)+len(n.wildcard_children))
+	for _, key := range n.static_keys {
+		children = append(children, n.static_children[key])
+	}
+	for _, name := range n.params_keys {
+		children = append(children, n.params_children[name])
+	}
+	return append(children, n.wildcard_children...)
+}
@@ -0,0 +1,40 @@
+package radix_test
+
+import (
+	"strings"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToDOT(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"users"}, "users")
+	tree.Add([]string{"users", ":id", "posts"}, "user_posts")
+	tree.Add([]string{"files", "*filepath"}, "files")
+
+	expected := `digraph radix {
+	n0 [label="<root>", shape=circle, color=black];
+	n1 [label="files", shape=circle, color=black];
+	n2 [label="*filepath", shape=doublecircle, color=red];
+	n1 -> n2;
+	n0 -> n1;
+	n3 [label="users", shape=doublecircle, color=black];
+	n4 [label=":id", shape=circle, color=blue];
+	n5 [label="posts", shape=doublecircle, color=black];
+	n4 -> n5;
+	n3 -> n4;
+	n0 -> n3;
+}
+`
+	assert.Equal(t, expected, tree.ToDOT())
+}
+
+func TestToDOTEmptyTree(t *testing.T) {
+	dot := radix.NewRadixTree().ToDOT()
+	assert.True(t, strings.HasPrefix(dot, "digraph radix {\n"))
+	assert.Contains(t, dot, `n0 [label="<root>", shape=circle, color=black];`)
+	assert.NotContains(t, dot, "->")
+}
@@ -0,0 +1,58 @@
+package radix
+
+import "slices"
+
+// StructuralEqual reports whether r and other hold the same routes in the
+// same shape: matching node types, segments, param names, constraints and
+// flags, the same children, and handlers that handlerEq considers equal.
+// Wildcard siblings must also be registered in the same order. Unlike
+// NodeWrapper.Equal it never compares node identity. A nil handlerEq
+// compares handlers with ==.
+func (r *RadixTree) StructuralEqual(other *RadixTree, handlerEq func(a, b Handler) bool) bool {
+	if handlerEq == nil {
+		handlerEq = func(a, b Handler) bool { return a == b }
+	}
+	return nodesEqual(r.root.Load(), other.root.Load(), handlerEq)
+}
+
+func nodesEqual(a, b *Node, handlerEq func(a, b Handler) bool) bool {
+	if a.nodeType != b.nodeType || a.path != b.path || a.paramName != b.paramName ||
+		a.isFallthrough != b.isFallthrough || a.matchesEmpty != b.matchesEmpty ||
+		a.minSegments != b.minSegments ||
+		(a.constraint == nil) != (b.constraint == nil) ||
+		a.constraint != nil && a.constraint.String() != b.constraint.String() ||
+		(a.optionalChild == nil) != (b.optionalChild == nil) ||
+		!slices.Equal(a.defaultValues, b.defaultValues) {
+		return false
+	}
+	if !handlersEqual(a.handler, b.handler, handlerEq) ||
+		!slices.EqualFunc(a.methods, b.methods, func(x, y methodHandler) bool {
+			return x.method == y.method && handlersEqual(x.handler, y.handler, handlerEq)
+		}) {
+		return false
+	}
+
+	if len(a.static_children) != len(b.static_children) || len(a.params_children) != len(b.params_children) {
+		return false
+	}
+	for key, child := range a.static_children {
+		if other, ok := b.static_children[key]; !ok || !nodesEqual(child, other, handlerEq) {
+			return false
+		}
+	}
+	for name, child := range a.params_children {
+		if other, ok := b.params_children[name]; !ok || !nodesEqual(child, other, handlerEq) {
+			return false
+		}
+	}
+	return slices.EqualFunc(a.wildcard_children, b.wildcard_children, func(x, y *Node) bool {
+		return nodesEqual(x, y, handlerEq)
+	})
+}
+
+func handlersEqual(a, b Handler, handlerEq func(a, b Handler) bool) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return handlerEq(a, b)
+}
@@ -0,0 +1,73 @@
+package radix_test
+
+import (
+	"regexp"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructuralEqual(t *testing.T) {
+	build := func(order []int) *radix.RadixTree {
+		tree := radix.NewRadixTree()
+		adds := []func(){
+			func() { tree.Add([]string{"users", ":id"}, "user_show") },
+			func() { tree.Add([]string{"api", "v1"}, "api_v1") },
+			func() { tree.AddMethod("GET", []string{"users"}, "users_get") },
+			func() {
+				tree.AddWithConstraints([]string{"posts", ":id"}, "post_show", map[string]*regexp.Regexp{"id": regexp.MustCompile(`^\d+$`)})
+			},
+		}
+		for _, i := range order {
+			adds[i]()
+		}
+		tree.Add([]string{"files", "*filepath"}, "files")
+		return tree
+	}
+
+	a, b := build([]int{0, 1, 2, 3}), build([]int{3, 2, 1, 0})
+	assert.True(t, a.StructuralEqual(b, nil), "Registration order of non-wildcards should not matter")
+	assert.True(t, a.StructuralEqual(a.Clone(), nil))
+
+	source := build([]int{0, 1, 2})
+	merged := radix.NewRadixTree()
+	_, err := merged.Merge(source)
+	assert.Nil(t, err)
+	assert.True(t, merged.StructuralEqual(source, nil))
+
+	b.Delete([]string{"api", "v1"})
+	assert.False(t, a.StructuralEqual(b, nil))
+	b.Add([]string{"api", "v1"}, "other")
+	assert.False(t, a.StructuralEqual(b, nil), "Handlers should be compared")
+	assert.True(t, a.StructuralEqual(b, func(x, y radix.Handler) bool { return true }))
+}
+
+func TestStructuralEqualDifferences(t *testing.T) {
+	base := func() *radix.RadixTree {
+		tree := radix.NewRadixTree()
+		tree.Add([]string{"users", ":id"}, "user")
+		return tree
+	}
+
+	renamed := base()
+	renamed.RenameParam([]string{"users", ":id"}, "user_id")
+	assert.False(t, base().StructuralEqual(renamed, nil))
+
+	constrained := radix.NewRadixTree()
+	constrained.AddWithConstraints([]string{"users", ":id"}, "user", map[string]*regexp.Regexp{"id": regexp.MustCompile(`^\d+$`)})
+	assert.False(t, base().StructuralEqual(constrained, nil))
+
+	fallthrough_ := radix.NewRadixTree()
+	fallthrough_.AddFallthrough([]string{"users", ":id"}, "user")
+	assert.False(t, base().StructuralEqual(fallthrough_, nil))
+
+	wildcards := func(names ...string) *radix.RadixTree {
+		tree := radix.NewRadixTree()
+		for _, name := range names {
+			tree.Add([]string{"files", name}, "files")
+		}
+		return tree
+	}
+	assert.False(t, wildcards("*a", "*b").StructuralEqual(wildcards("*b", "*a"), nil), "Wildcard order should matter")
+}
@@ -0,0 +1,18 @@
+package radix
+
+import "errors"
+
+var (
+	ErrHandlerExists      = errors.New("handler already exists for this path")
+	ErrPathNotFound       = errors.New("path not found")
+	ErrNodeExists         = errors.New("node already exists at this path")
+	ErrWildcardNotLast    = errors.New("wildcard must be the last segment")
+	ErrConstraintConflict = errors.New("parameter already has a different constraint")
+	ErrAmbiguousRoute     = errors.New("ambiguous route")
+	ErrEmptyName          = errors.New("parameter or wildcard has no name")
+	ErrNilHandler         = errors.New("handler cannot be nil")
+	ErrDuplicateParam     = errors.New("parameter name repeated in path")
+	ErrMissingParam       = errors.New("no value for parameter")
+	ErrTooManyRoutes      = errors.New("tree holds too many routes")
+	ErrHandlerMismatch    = errors.New("handler does not match")
+)
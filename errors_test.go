@@ -0,0 +1,55 @@
+package radix_test
+
+import (
+	"errors"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddErrors(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id"}, "handler1")
+
+	_, err := tree.Add([]string{"users", ":id"}, "handler2")
+	assert.True(t, errors.Is(err, radix.ErrHandlerExists), "Duplicate route should return ErrHandlerExists")
+
+	_, err = tree.Add([]string{}, "root")
+	assert.Nil(t, err)
+	_, err = tree.Add([]string{}, "root2")
+	assert.True(t, errors.Is(err, radix.ErrHandlerExists), "Duplicate root should return ErrHandlerExists")
+
+	_, err = tree.Add([]string{"files", "*filepath", "extra"}, "handler")
+	assert.True(t, errors.Is(err, radix.ErrWildcardNotLast), "Segment after wildcard should return ErrWildcardNotLast")
+}
+
+func TestDeleteErrors(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id", "posts"}, "handler")
+
+	err := tree.Delete([]string{"admin"})
+	assert.True(t, errors.Is(err, radix.ErrPathNotFound), "Unknown path should return ErrPathNotFound")
+
+	err = tree.Delete([]string{"users", ":id"})
+	assert.True(t, errors.Is(err, radix.ErrPathNotFound), "Intermediate node without handler should return ErrPathNotFound")
+
+	err = tree.Delete([]string{"users", ":id", "posts"})
+	assert.Nil(t, err)
+}
+
+func TestNilHandler(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users"}, "users")
+
+	_, err := tree.Add([]string{"x"}, nil)
+	assert.ErrorIs(t, err, radix.ErrNilHandler)
+	_, err = tree.Add([]string{"files", "*filepath"}, nil)
+	assert.ErrorIs(t, err, radix.ErrNilHandler)
+	assert.ErrorIs(t, tree.AddMethod("GET", []string{"users"}, nil), radix.ErrNilHandler)
+	assert.ErrorIs(t, tree.SetRoot(nil), radix.ErrNilHandler)
+
+	assert.Equal(t, uint32(1), tree.Size())
+	assert.Equal(t, 1, tree.Count())
+	assert.Len(t, tree.Get([]string{"x"}), 0)
+}
@@ -0,0 +1,23 @@
+package radix
+
+// AddDanglingForTest creates the nodes for path without registering a route
+// on them, leaving the dead structure a pruning bug would.
+func (r *RadixTree) AddDanglingForTest(path []string) error {
+	return r.update(func(root *Node) error {
+		trail, err := r.addRoute(root, path, func(*Node) error { return nil }, nil)
+		if err != nil {
+			return err
+		}
+		for _, n := range trail {
+			n.nodeSize--
+		}
+		return nil
+	})
+}
+
+// SameNodeForTest reports whether r and other hold the very same node at
+// path, as trees sharing structure do.
+func (r *RadixTree) SameNodeForTest(other *RadixTree, path []string) bool {
+	node := r.findNode(r.root.Load(), path)
+	return node != nil && node == other.findNode(other.root.Load(), path)
+}
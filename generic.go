@@ -0,0 +1,70 @@
+package radix
+
+// Tree is a RadixTree whose handlers all have type H, sparing callers the
+// type assertions on Route.Handler. It delegates to an untyped tree,
+// available through Untyped for the operations it does not wrap.
+type Tree[H any] struct {
+	tree *RadixTree
+}
+
+// TypedRoute is a Route whose handler has type H.
+type TypedRoute[H any] struct {
+	Handler     H
+	Params      Params
+	Fallthrough bool
+	Method      string
+}
+
+func NewTree[H any]() *Tree[H] {
+	return &Tree[H]{tree: NewRadixTree()}
+}
+
+func NewTreeWithOptions[H any](opts Options) *Tree[H] {
+	return &Tree[H]{tree: NewRadixTreeWithOptions(opts)}
+}
+
+// Untyped returns the underlying tree. Handlers added through it must have
+// type H too.
+func (t *Tree[H]) Untyped() *RadixTree {
+	return t.tree
+}
+
+func (t *Tree[H]) Add(path []string, handler H) (*NodeWrapper, error) {
+	return t.tree.Add(path, handler)
+}
+
+func (t *Tree[H]) AddMethod(method string, path []string, handler H) error {
+	return t.tree.AddMethod(method, path, handler)
+}
+
+func (t *Tree[H]) Get(path []string) []TypedRoute[H] {
+	return typedRoutes[H](t.tree.Get(path))
+}
+
+func (t *Tree[H]) GetMethod(method string, path []string) []TypedRoute[H] {
+	return typedRoutes[H](t.tree.GetMethod(method, path))
+}
+
+func (t *Tree[H]) Delete(path []string) error {
+	return t.tree.Delete(path)
+}
+
+func (t *Tree[H]) Size() uint32 {
+	return t.tree.Size()
+}
+
+func typedRoutes[H any](routes Routes) []TypedRoute[H] {
+	if len(routes) == 0 {
+		return nil
+	}
+	typed := make([]TypedRoute[H], len(routes))
+	for i, route := range routes {
+		typed[i] = TypedRoute[H]{
+			Handler:     route.Handler.(H),
+			Params:      route.Params,
+			Fallthrough: route.Fallthrough,
+			Method:      route.Method,
+		}
+	}
+	return typed
+}
@@ -0,0 +1,43 @@
+package radix_test
+
+import (
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedTree(t *testing.T) {
+	tree := radix.NewTree[string]()
+
+	_, err := tree.Add([]string{"users", ":id"}, "user_show")
+	assert.Nil(t, err)
+	assert.Nil(t, tree.AddMethod("POST", []string{"users"}, "user_create"))
+	assert.Equal(t, uint32(2), tree.Size())
+
+	routes := tree.Get([]string{"users", "42"})
+	assert.Len(t, routes, 1)
+	var handler string = routes[0].Handler
+	assert.Equal(t, "user_show", handler)
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, routes[0].Params)
+
+	routes = tree.GetMethod("POST", []string{"users"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "user_create", routes[0].Handler)
+	assert.Equal(t, "POST", routes[0].Method)
+
+	assert.Nil(t, tree.Delete([]string{"users", ":id"}))
+	assert.Empty(t, tree.Get([]string{"users", "42"}))
+	assert.Equal(t, uint32(1), tree.Untyped().Size())
+}
+
+func TestTypedTreeFuncHandlers(t *testing.T) {
+	type handlerFunc func() int
+	tree := radix.NewTreeWithOptions[handlerFunc](radix.Options{ParamPrefix: '{', ParamSuffix: '}'})
+
+	tree.Add([]string{"answer", "{n}"}, func() int { return 42 })
+
+	routes := tree.Get([]string{"answer", "x"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, 42, routes[0].Handler())
+}
@@ -0,0 +1,55 @@
+package radix
+
+import (
+	"context"
+	"net/http"
+)
+
+type paramsContextKey struct{}
+
+// Handler adapts the tree to net/http. Each request path is split, still
+// escaped as GetURL splits it, with ParsePath and looked up with GetMethod
+// using the request method; the highest-priority route whose handler is an
+// http.Handler (such as an http.HandlerFunc) serves the request, with its
+// Params stored in the request context. Requests without such a route are passed to notFound or,
+// when it is nil, to the tree's NotFound handler if that is an http.Handler,
+// and to http.NotFound otherwise.
+func (r *RadixTree) Handler(notFound http.Handler) http.Handler {
+	if notFound == nil {
+		notFound = asHTTPHandler(r.notFound)
+	}
+	if notFound == nil {
+		notFound = http.HandlerFunc(http.NotFound)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		for _, route := range r.GetMethod(req.Method, ParsePath(req.URL.EscapedPath())) {
+			handler := asHTTPHandler(route.Handler)
+			if handler == nil {
+				continue
+			}
+			ctx := context.WithValue(req.Context(), paramsContextKey{}, route.Params)
+			handler.ServeHTTP(w, req.WithContext(ctx))
+			return
+		}
+		notFound.ServeHTTP(w, req)
+	})
+}
+
+// asHTTPHandler returns h as an http.Handler, or nil if it is not one.
+func asHTTPHandler(h Handler) http.Handler {
+	switch h := h.(type) {
+	case http.Handler:
+		return h
+	case func(http.ResponseWriter, *http.Request):
+		return http.HandlerFunc(h)
+	}
+	return nil
+}
+
+// ParamsFromContext returns the Params stored by the Handler adapter. When
+// several routes match a request, these are the params of the route that
+// served it, i.e. the first (highest-priority) match.
+func ParamsFromContext(ctx context.Context) (Params, bool) {
+	params, ok := ctx.Value(paramsContextKey{}).(Params)
+	return params, ok
+}
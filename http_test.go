@@ -0,0 +1,150 @@
+package radix_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(handler http.Handler, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder
+}
+
+func TestHTTPHandler(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "home")
+	}))
+	tree.Add([]string{"users", ":id"}, func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "user")
+	})
+	tree.Add([]string{"opaque"}, "not an http handler")
+
+	handler := tree.Handler(nil)
+
+	recorder := serve(handler, "/")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "home", recorder.Body.String())
+
+	recorder = serve(handler, "/users/42/")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "user", recorder.Body.String())
+
+	recorder = serve(handler, "/missing")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = serve(handler, "/opaque")
+	assert.Equal(t, http.StatusNotFound, recorder.Code, "Non-HTTP handlers should not be served")
+}
+
+func TestHTTPHandlerCustomNotFound(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	handler := tree.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	recorder := serve(handler, "/missing")
+	assert.Equal(t, http.StatusTeapot, recorder.Code)
+}
+
+func TestHTTPHandlerTreeNotFound(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.SetNotFound(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	assert.Equal(t, http.StatusTeapot, serve(tree.Handler(nil), "/missing").Code)
+
+	custom := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	assert.Equal(t, http.StatusGone, serve(tree.Handler(custom), "/missing").Code, "Explicit notFound should take precedence")
+
+	tree.SetNotFound("not an http handler")
+	assert.Equal(t, http.StatusNotFound, serve(tree.Handler(nil), "/missing").Code)
+}
+
+func TestHTTPHandlerSkipsNonHTTPRoutes(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"files", ":name"}, "metadata")
+	tree.Add([]string{"files", "*filepath"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "files")
+	}))
+
+	recorder := serve(tree.Handler(nil), "/files/readme.txt")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "files", recorder.Body.String())
+}
+
+func TestParamsFromContext(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"users", ":id"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params, ok := radix.ParamsFromContext(r.Context())
+		assert.True(t, ok)
+		id, _ := params.Get("id")
+		io.WriteString(w, id[0])
+	}))
+	tree.Add([]string{"users", "*rest"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "rest")
+	}))
+
+	recorder := serve(tree.Handler(nil), "/users/42")
+	assert.Equal(t, "42", recorder.Body.String(), "Params of the best match should be stored")
+
+	_, ok := radix.ParamsFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context())
+	assert.False(t, ok, "Plain contexts should not carry params")
+}
+
+func TestHTTPHandlerDecodeParams(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{DecodeParams: true})
+	tree.Add([]string{"files", ":name"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params, _ := radix.ParamsFromContext(r.Context())
+		name, _ := params.Get("name")
+		io.WriteString(w, name[0])
+	}))
+	handler := tree.Handler(nil)
+
+	recorder := serve(handler, "/files/a%2520b")
+	assert.Equal(t, "a%20b", recorder.Body.String(), "Values should be decoded once")
+	routes, err := tree.GetURL("/files/a%2520b")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a%20b"}, routes[0].Params[0].Values, "The adapter should agree with GetURL")
+
+	recorder = serve(handler, "/files/a%2Fb")
+	assert.Equal(t, http.StatusOK, recorder.Code, "An encoded slash should stay in its segment")
+	assert.Equal(t, "a/b", recorder.Body.String())
+}
+
+func TestHTTPHandlerMethods(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.AddMethod(http.MethodGet, []string{"users"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "list")
+	}))
+	tree.AddMethod(http.MethodPost, []string{"users"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "create")
+	}))
+
+	handler := tree.Handler(nil)
+
+	recorder := serve(handler, "/users")
+	assert.Equal(t, "list", recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/users", nil))
+	assert.Equal(t, "create", recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/users", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
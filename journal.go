@@ -0,0 +1,228 @@
+package radix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+)
+
+type journalOp string
+
+const (
+	journalAdd          journalOp = "add"
+	journalChain        journalOp = "add_chain"
+	journalDelete       journalOp = "delete"
+	journalPrune        journalOp = "delete_subtree"
+	journalRenameStatic journalOp = "rename_static"
+	journalRenameParam  journalOp = "rename_param"
+	journalClear        journalOp = "clear"
+	journalCompact      journalOp = "compact"
+)
+
+type journalEntry struct {
+	Op     journalOp `json:"op"`
+	Method string    `json:"method,omitempty"`
+	Path   []string  `json:"path"`
+	// NewName is the segment or param name a rename gives the node.
+	NewName string `json:"new_name,omitempty"`
+	// The remaining fields describe an added route beyond its path.
+	Constraints map[string]string `json:"constraints,omitempty"`
+	Optional    bool              `json:"optional,omitempty"`
+	Defaults    []string          `json:"defaults,omitempty"`
+	MinSegments int               `json:"min_segments,omitempty"`
+	Fallthrough bool              `json:"fallthrough,omitempty"`
+}
+
+// WithJournal enables recording of every successful write to the tree, in
+// the order they were applied, and returns the tree. Added routes are
+// recorded with the constraints, optional defaults, minimum wildcard
+// lengths and fallthrough flags they were registered with, so that Replay
+// rebuilds the same tree. Writes moving in routes from another tree, such
+// as ReplaceSubtree and Mount, are recorded as the deletions and additions
+// they amount to. Handlers and route metadata are not recorded; Replay asks
+// the caller to resolve handlers again.
+func (r *RadixTree) WithJournal() *RadixTree {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.journal == nil {
+		r.journal = []journalEntry{}
+	}
+	return r
+}
+
+// WriteJournal writes the recorded operations to w as JSON lines.
+func (r *RadixTree) WriteJournal(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.journal == nil {
+		return errors.New("journal is not enabled")
+	}
+	encoder := json.NewEncoder(w)
+	for _, entry := range r.journal {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Replay builds a new tree by applying the operations read from a journal
+// produced by WriteJournal, calling resolve for the handler of each added
+// route. For a route extended with AddChain, resolve is called once per
+// recorded AddChain, and a Chain it returns is appended handler by handler.
+// The returned tree has journaling enabled and default options.
+func Replay(rd io.Reader, resolve func(path []string) Handler) (*RadixTree, error) {
+	return ReplayWithOptions(rd, Options{}, resolve)
+}
+
+// ReplayWithOptions replays a journal like Replay into a tree created with
+// opts. The journal records routes in the syntax of the tree that wrote it,
+// so opts should match that tree's options, custom markers included.
+func ReplayWithOptions(rd io.Reader, opts Options, resolve func(path []string) Handler) (*RadixTree, error) {
+	tree := NewRadixTreeWithOptions(opts).WithJournal()
+	decoder := json.NewDecoder(rd)
+	for {
+		var entry journalEntry
+		if err := decoder.Decode(&entry); err == io.EOF {
+			return tree, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		var err error
+		switch entry.Op {
+		case journalAdd:
+			err = tree.replayAdd(entry, resolve(entry.Path))
+		case journalChain:
+			handler := resolve(entry.Path)
+			if chain, ok := handler.(Chain); ok {
+				err = tree.AddChain(entry.Path, chain...)
+			} else {
+				err = tree.AddChain(entry.Path, handler)
+			}
+		case journalDelete:
+			if entry.Method != "" {
+				err = tree.DeleteMethod(entry.Method, entry.Path)
+			} else {
+				err = tree.Delete(entry.Path)
+			}
+		case journalPrune:
+			_, err = tree.DeleteSubtree(entry.Path)
+		case journalRenameStatic:
+			err = tree.RenameStatic(entry.Path, entry.NewName)
+		case journalRenameParam:
+			err = tree.RenameParam(entry.Path, entry.NewName)
+		case journalClear:
+			tree.Clear()
+		case journalCompact:
+			tree.Compact()
+		default:
+			err = fmt.Errorf("unknown journal operation %q", entry.Op)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("replay %s %v: %w", entry.Op, entry.Path, err)
+		}
+	}
+}
+
+// replayAdd registers handler as the route entry describes.
+func (r *RadixTree) replayAdd(entry journalEntry, handler Handler) error {
+	constraints := make(map[string]*regexp.Regexp, len(entry.Constraints))
+	for name, expr := range entry.Constraints {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return err
+		}
+		constraints[name] = re
+	}
+	assign := assignHandler(handler)
+	if entry.Method != "" {
+		assign = assignMethod(entry.Method, handler)
+	}
+	if entry.MinSegments > 0 {
+		if err := r.checkMinSegments(entry.Path, entry.MinSegments); err != nil {
+			return err
+		}
+		assign = withMinSegments(assign, entry.MinSegments)
+	}
+	return r.update(func(root *Node) error {
+		var trail []*Node
+		var err error
+		if entry.Optional {
+			trail, err = r.addOptional(root, entry.Path, assign, constraints, entry.Defaults)
+		} else {
+			trail, err = r.addRoute(root, entry.Path, assign, constraints)
+		}
+		if err != nil {
+			return err
+		}
+		if entry.Fallthrough && entry.Method == "" {
+			trail[len(trail)-1].isFallthrough = true
+		}
+		r.record(entry)
+		return nil
+	})
+}
+
+func (r *RadixTree) record(entry journalEntry) {
+	if r.journal != nil {
+		entry.Path = slices.Clone(entry.Path)
+		entry.Defaults = slices.Clone(entry.Defaults)
+		r.journal = append(r.journal, entry)
+	}
+}
+
+// recordRoutes records an addition for every route at or below the node at
+// the end of trail, reached through path, in the order WalkSorted visits
+// them.
+func (r *RadixTree) recordRoutes(trail []*Node, path []string) {
+	if r.journal == nil {
+		return
+	}
+	node := trail[len(trail)-1]
+	if node.handler != nil {
+		r.record(journalRoute(trail, "", path))
+	}
+	for _, mh := range node.methods {
+		r.record(journalRoute(trail, mh.method, path))
+	}
+	for _, child := range node.sortedChildren() {
+		r.recordRoutes(extend(trail, child), extend(path, r.label(child)))
+	}
+}
+
+// journalRoute returns the journal entry adding the route for method on the
+// node at the end of trail, with everything it was registered with.
+func journalRoute(trail []*Node, method string, path []string) journalEntry {
+	node := trail[len(trail)-1]
+	entry := journalEntry{Op: journalAdd, Method: method, Path: path, MinSegments: node.minSegments}
+	for _, n := range trail {
+		if n.constraint != nil {
+			if entry.Constraints == nil {
+				entry.Constraints = make(map[string]string)
+			}
+			entry.Constraints[n.paramName] = n.constraint.String()
+		}
+	}
+	if len(trail) > 1 && trail[len(trail)-2].optionalChild == node {
+		entry.Optional, entry.Defaults = true, node.defaultValues
+	}
+	entry.Fallthrough = method == "" && node.isFallthrough
+	return entry
+}
+
+// constraintSources returns the expressions of constraints, as journal
+// entries record them.
+func constraintSources(constraints map[string]*regexp.Regexp) map[string]string {
+	if len(constraints) == 0 {
+		return nil
+	}
+	sources := make(map[string]string, len(constraints))
+	for name, re := range constraints {
+		sources[name] = re.String()
+	}
+	return sources
+}
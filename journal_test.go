@@ -0,0 +1,176 @@
+package radix_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func routeTable(tree *radix.RadixTree) map[string]radix.Handler {
+	table := make(map[string]radix.Handler)
+	tree.Walk(func(path []string, handler radix.Handler) bool {
+		table["/"+strings.Join(path, "/")] = handler
+		return true
+	})
+	return table
+}
+
+func TestJournalReplay(t *testing.T) {
+	tree := radix.NewRadixTree().WithJournal()
+
+	tree.Add([]string{}, "/")
+	tree.Add([]string{"users", ":id"}, "/users/:id")
+	tree.Add([]string{"files", "*second"}, "/files/*second")
+	tree.Add([]string{"files", "*first"}, "/files/*first")
+	tree.Add([]string{"admin"}, "/admin")
+	tree.Delete([]string{"admin"})
+	tree.Add([]string{"users", ":id"}, "conflict")
+	tree.Delete([]string{"missing"})
+	tree.Add([]string{"admin", "users"}, "/admin/users")
+	tree.Add([]string{"admin", "posts"}, "/admin/posts")
+	tree.DeleteSubtree([]string{"admin"})
+
+	var journal bytes.Buffer
+	assert.Nil(t, tree.WriteJournal(&journal))
+
+	replayed, err := radix.Replay(&journal, func(path []string) radix.Handler {
+		return "/" + strings.Join(path, "/")
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, tree.Size(), replayed.Size())
+	assert.Equal(t, routeTable(tree), routeTable(replayed))
+
+	// Wildcard priority follows insertion order, which the journal preserves.
+	expected := tree.Get([]string{"files", "a", "b"})
+	actual := replayed.Get([]string{"files", "a", "b"})
+	assert.Len(t, actual, len(expected))
+	for i := range expected {
+		assert.Equal(t, expected[i].Handler, actual[i].Handler)
+		assert.Equal(t, expected[i].Params, actual[i].Params)
+	}
+}
+
+func TestJournalRecordsEveryWrite(t *testing.T) {
+	resolve := func(path []string) radix.Handler {
+		if len(path) > 0 && path[0] == "admin" {
+			return "middleware"
+		}
+		return "/" + strings.Join(path, "/")
+	}
+	tree := radix.NewRadixTree().WithJournal()
+	add := func(path ...string) { tree.Add(path, resolve(path)) }
+
+	add("a")
+	tree.Clear()
+	add("a")
+	add("a", "z")
+	assert.Nil(t, tree.RenameStatic([]string{"a"}, "b"))
+
+	path := []string{"users", ":id"}
+	_, err := tree.AddWithConstraints(path, resolve(path), map[string]*regexp.Regexp{"id": regexp.MustCompile(`^\d+$`)})
+	assert.Nil(t, err)
+	assert.Nil(t, tree.AddMethod("GET", path, resolve(path)))
+	path = []string{"users", ":id", "edit"}
+	_, err = tree.AddFallthrough(path, resolve(path))
+	assert.Nil(t, err)
+	assert.Nil(t, tree.RenameParam([]string{"users", ":id"}, "uid"))
+
+	path = []string{"posts", ":page"}
+	_, err = tree.AddOptional(path, resolve(path), "1")
+	assert.Nil(t, err)
+	path = []string{"files", "*path"}
+	_, err = tree.AddWildcard(path, resolve(path), 2)
+	assert.Nil(t, err)
+	_, err = tree.AddWildcard(path, resolve(path), 0)
+	assert.Nil(t, err)
+	assert.Nil(t, tree.AddChain([]string{"admin"}, "middleware"))
+	assert.Nil(t, tree.AddChain([]string{"admin"}, "middleware"))
+
+	sub := radix.NewRadixTree()
+	_, err = sub.AddWithConstraints([]string{":version"}, "/api/:version", map[string]*regexp.Regexp{"version": regexp.MustCompile(`^v\d$`)})
+	assert.Nil(t, err)
+	_, err = sub.AddFallthrough([]string{":version", "*rest"}, "/api/:version/*rest")
+	assert.Nil(t, err)
+	_, err = sub.AddOptional([]string{"docs", ":section"}, "/api/docs/:section", "intro")
+	assert.Nil(t, err)
+	assert.Nil(t, tree.Mount([]string{"api"}, sub))
+
+	assert.Nil(t, tree.AddDanglingForTest([]string{"stale", "branch"}))
+	assert.Equal(t, 2, tree.Compact())
+
+	var journal bytes.Buffer
+	assert.Nil(t, tree.WriteJournal(&journal))
+	replayed, err := radix.Replay(&journal, resolve)
+	assert.Nil(t, err)
+	assert.True(t, tree.StructuralEqual(replayed, func(a, b radix.Handler) bool { return assert.ObjectsAreEqual(a, b) }),
+		"Replay should rebuild the same tree:\n%s\n%s", tree, replayed)
+}
+
+func TestJournalDisabled(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users"}, "users")
+
+	var journal bytes.Buffer
+	assert.NotNil(t, tree.WriteJournal(&journal), "Writing a journal should fail when journaling is off")
+}
+
+func TestReplayInvalidJournal(t *testing.T) {
+	resolve := func(path []string) radix.Handler { return "handler" }
+
+	_, err := radix.Replay(strings.NewReader(`{"op":"delete","path":["missing"]}`), resolve)
+	assert.NotNil(t, err, "Replaying a delete of a missing route should fail")
+
+	_, err = radix.Replay(strings.NewReader(`{"op":"rename","path":["a"]}`), resolve)
+	assert.NotNil(t, err, "Unknown operations should be rejected")
+
+	_, err = radix.Replay(strings.NewReader(`not json`), resolve)
+	assert.NotNil(t, err, "Malformed journals should be rejected")
+}
+
+func TestJournalReplayMethods(t *testing.T) {
+	tree := radix.NewRadixTree().WithJournal()
+
+	tree.AddMethod("GET", []string{"users"}, "/users")
+	tree.AddMethod("POST", []string{"users"}, "/users")
+	tree.AddMethod("DELETE", []string{"users"}, "/users")
+	tree.DeleteMethod("DELETE", []string{"users"})
+
+	var journal bytes.Buffer
+	assert.Nil(t, tree.WriteJournal(&journal))
+
+	replayed, err := radix.Replay(&journal, func(path []string) radix.Handler {
+		return "/" + strings.Join(path, "/")
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), replayed.Size())
+	assert.Len(t, replayed.GetMethod("POST", []string{"users"}), 1)
+	assert.Len(t, replayed.GetMethod("DELETE", []string{"users"}), 0)
+}
+
+func TestReplayWithOptions(t *testing.T) {
+	opts := radix.Options{ParamPrefix: '{', ParamSuffix: '}', StrictWildcards: true}
+	tree := radix.NewRadixTreeWithOptions(opts).WithJournal()
+	tree.Add([]string{"users", "{id}"}, "/users/{id}")
+	tree.Add([]string{"files", "*path"}, "/files/*path")
+
+	var journal bytes.Buffer
+	assert.Nil(t, tree.WriteJournal(&journal))
+	resolve := func(path []string) radix.Handler { return "/" + strings.Join(path, "/") }
+
+	replayed, err := radix.ReplayWithOptions(bytes.NewReader(journal.Bytes()), opts, resolve)
+	assert.Nil(t, err)
+	routes := replayed.Get([]string{"users", "42"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, routes[0].Params)
+	_, err = replayed.Add([]string{"files", "{name}"}, "name")
+	assert.ErrorIs(t, err, radix.ErrAmbiguousRoute, "Replayed tree should keep the options")
+
+	replayed, err = radix.Replay(bytes.NewReader(journal.Bytes()), resolve)
+	assert.Nil(t, err)
+	assert.Len(t, replayed.Get([]string{"users", "42"}), 0, "Default markers read {id} as a static segment")
+}
@@ -0,0 +1,49 @@
+package radix
+
+import "encoding/json"
+
+type routeEntry struct {
+	Path   []string `json:"path"`
+	Method string   `json:"method,omitempty"`
+}
+
+// MarshalRoutes encodes the registered route patterns, without their
+// handlers, as a JSON array of {"path": [...]} objects. Routes added with
+// AddMethod also carry their "method".
+func (r *RadixTree) MarshalRoutes() ([]byte, error) {
+	entries := []routeEntry{}
+	r.walk(func(path []string, method string, handler Handler) bool {
+		entries = append(entries, routeEntry{Path: path, Method: method})
+		return true
+	})
+	return json.Marshal(entries)
+}
+
+// LoadRoutes builds a tree from the output of MarshalRoutes, calling resolve
+// to obtain the handler for each route. The tree has default options.
+func LoadRoutes(data []byte, resolve func(path []string) Handler) (*RadixTree, error) {
+	return LoadRoutesWithOptions(data, Options{}, resolve)
+}
+
+// LoadRoutesWithOptions loads routes like LoadRoutes into a tree created
+// with opts. MarshalRoutes writes routes in the syntax of the tree encoding
+// them, so opts should match that tree's options, custom markers included.
+func LoadRoutesWithOptions(data []byte, opts Options, resolve func(path []string) Handler) (*RadixTree, error) {
+	var entries []routeEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	tree := NewRadixTreeWithOptions(opts)
+	for _, entry := range entries {
+		var err error
+		if entry.Method != "" {
+			err = tree.AddMethod(entry.Method, entry.Path, resolve(entry.Path))
+		} else {
+			_, err = tree.Add(entry.Path, resolve(entry.Path))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tree, nil
+}
@@ -0,0 +1,85 @@
+package radix_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalRoutes(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"api", "v1", "users", ":id"}, "user_show")
+
+	data, err := tree.MarshalRoutes()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"path": ["api", "v1", "users", ":id"]}]`, string(data))
+
+	data, err = radix.NewRadixTree().MarshalRoutes()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[]`, string(data))
+}
+
+func TestLoadRoutesRoundTrip(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{}, "/")
+	tree.Add([]string{"users", ":id"}, "/users/:id")
+	tree.Add([]string{"files", "*filepath"}, "/files/*filepath")
+	tree.Add([]string{"files", "*filepath"}, "/files/*filepath")
+	tree.AddMethod("POST", []string{"users"}, "/users")
+
+	data, err := tree.MarshalRoutes()
+	assert.Nil(t, err)
+
+	loaded, err := radix.LoadRoutes(data, func(path []string) radix.Handler {
+		return "/" + strings.Join(path, "/")
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, tree.Count(), loaded.Count())
+	assert.Equal(t, tree.Size(), loaded.Size())
+
+	routes := loaded.Get([]string{"users", "42"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "/users/:id", routes[0].Handler)
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, routes[0].Params)
+
+	routes = loaded.GetMethod("POST", []string{"users"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "POST", routes[0].Method)
+
+	assert.Len(t, loaded.Get([]string{"files", "a", "b"}), 2)
+}
+
+func TestLoadRoutesWithOptions(t *testing.T) {
+	opts := radix.Options{ParamPrefix: '{', ParamSuffix: '}'}
+	tree := radix.NewRadixTreeWithOptions(opts)
+	tree.Add([]string{"users", "{id}"}, "/users/{id}")
+
+	data, err := tree.MarshalRoutes()
+	assert.Nil(t, err)
+	resolve := func(path []string) radix.Handler { return "/" + strings.Join(path, "/") }
+
+	loaded, err := radix.LoadRoutesWithOptions(data, opts, resolve)
+	assert.Nil(t, err)
+	routes := loaded.Get([]string{"users", "42"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, routes[0].Params)
+
+	loaded, err = radix.LoadRoutes(data, resolve)
+	assert.Nil(t, err)
+	assert.Len(t, loaded.Get([]string{"users", "42"}), 0, "Default markers read {id} as a static segment")
+}
+
+func TestLoadRoutesErrors(t *testing.T) {
+	resolve := func(path []string) radix.Handler { return "handler" }
+
+	_, err := radix.LoadRoutes([]byte(`not json`), resolve)
+	assert.NotNil(t, err)
+	var syntaxErr *json.SyntaxError
+	assert.ErrorAs(t, err, &syntaxErr)
+
+	_, err = radix.LoadRoutes([]byte(`[{"path": ["a"]}, {"path": ["a"]}]`), resolve)
+	assert.ErrorIs(t, err, radix.ErrHandlerExists)
+}
@@ -0,0 +1,33 @@
+package radix
+
+import "fmt"
+
+// Merge adds every route of other into r, as Mount would at the root, so
+// routes keep their constraints, optional params, minimum wildcard lengths,
+// fallthrough flags and metadata. Routes whose path already has a handler
+// in r, for the same method, are skipped and reported in the returned list,
+// in the order WalkSorted visits them, instead of aborting the merge; a
+// wildcard route gets a sibling instead, as Add would give it. Any
+// other failure, such as conflicting constraints, leaves r unchanged and is
+// returned. The merge is published as one change. Both trees must use the
+// same segment markers.
+func (r *RadixTree) Merge(other *RadixTree) ([][]string, error) {
+	if !r.sameSyntax(other) {
+		return nil, fmt.Errorf("merged tree uses different segment markers")
+	}
+	var conflicts [][]string
+	err := r.update(func(root *Node) error {
+		conflicts = nil
+		merged := cloneNode(other.root.Load(), r.version)
+		r.stampAll(merged)
+		if _, err := r.graft([]*Node{root}, merged, &conflicts); err != nil {
+			return err
+		}
+		r.recordRoutes([]*Node{merged}, []string{})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
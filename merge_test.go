@@ -0,0 +1,147 @@
+package radix_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users"}, "users")
+	tree.Add([]string{"users", ":id"}, "user_show")
+
+	other := radix.NewRadixTree()
+	other.Add([]string{"users", ":id"}, "other_user_show")
+	other.Add([]string{"users", ":id", "posts"}, "user_posts")
+	other.Add([]string{"files", "*filepath"}, "files")
+	other.Add([]string{}, "root")
+
+	conflicts, err := tree.Merge(other)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"users", ":id"}}, conflicts)
+	assert.Equal(t, uint32(5), tree.Size())
+
+	routes := tree.Get([]string{"users", "42"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "user_show", routes[0].Handler.(string), "Conflicting route should keep the existing handler")
+
+	routes = tree.Get([]string{"users", "42", "posts"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "user_posts", routes[0].Handler.(string))
+
+	assert.Len(t, tree.Get([]string{"files", "a", "b"}), 1)
+	assert.Len(t, tree.Get([]string{}), 1)
+
+	assert.Equal(t, uint32(4), other.Size(), "Merge should not modify the other tree")
+}
+
+func TestMergeEmpty(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users"}, "users")
+
+	conflicts, err := tree.Merge(radix.NewRadixTree())
+	assert.Nil(t, err)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, uint32(1), tree.Size())
+}
+
+func TestMergeDuplicateWildcards(t *testing.T) {
+	other := radix.NewRadixTree()
+	other.Add([]string{"a", "*x"}, "h1")
+	other.Add([]string{"a", "*x"}, "h2")
+
+	tree := radix.NewRadixTree()
+	conflicts, err := tree.Merge(other)
+	assert.Nil(t, err)
+	assert.Empty(t, conflicts, "Merging into an empty tree should report no conflicts")
+	assert.Equal(t, uint32(2), tree.Size())
+	assert.Equal(t, []string{"h1", "h2"}, handlers(tree.Get([]string{"a", "b"})))
+}
+
+func TestMergeMethods(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.AddMethod("GET", []string{"users"}, "list_users")
+
+	other := radix.NewRadixTree()
+	other.AddMethod("GET", []string{"users"}, "other_list_users")
+	other.AddMethod("POST", []string{"users"}, "create_user")
+
+	conflicts, err := tree.Merge(other)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"users"}}, conflicts)
+
+	routes := tree.GetMethod("POST", []string{"users"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "create_user", routes[0].Handler.(string))
+	assert.Equal(t, "POST", routes[0].Method)
+}
+
+func TestMergeKeepsRouteProperties(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"files", "*path"}, "files")
+
+	other := radix.NewRadixTree()
+	other.AddWithConstraints([]string{"users", ":id"}, "user_show", map[string]*regexp.Regexp{"id": regexp.MustCompile(`^\d+$`)})
+	other.AddOptional([]string{"posts", ":page"}, "posts", "1")
+	other.AddFallthrough([]string{"pages", ":slug"}, "page")
+	other.AddWithMeta([]string{"about"}, "about", "about_meta")
+	other.AddWildcard([]string{"files", "*path"}, "files_deep", 2)
+
+	conflicts, err := tree.Merge(other)
+	assert.Nil(t, err)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, uint32(6), tree.Size())
+
+	assert.Len(t, tree.Get([]string{"users", "abc"}), 0, "Constraints should be merged")
+	routes := tree.Get([]string{"posts"})
+	assert.Len(t, routes, 1, "Optional params should be merged")
+	assert.Equal(t, radix.Params{{Key: "page", Values: []string{"1"}}}, routes[0].Params)
+	route, found := tree.GetOne([]string{"pages", "x"}, func(radix.Route) bool { return false })
+	assert.False(t, found, "Fallthrough flags should be merged: %v", route)
+	assert.Equal(t, "about_meta", tree.Get([]string{"about"})[0].Meta)
+	assert.Equal(t, []string{"files"}, handlers(tree.Get([]string{"files", "a"})), "Minimum wildcard lengths should be merged")
+	assert.Equal(t, []string{"files", "files_deep"}, handlers(tree.Get([]string{"files", "a", "b"})))
+}
+
+func TestMergeIsAtomic(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.AddWithConstraints([]string{"users", ":id"}, "user_show", map[string]*regexp.Regexp{"id": regexp.MustCompile(`^\d+$`)})
+
+	other := radix.NewRadixTree()
+	other.Add([]string{"about"}, "about")
+	other.AddWithConstraints([]string{"users", ":id", "posts"}, "user_posts", map[string]*regexp.Regexp{"id": regexp.MustCompile(`^\w+$`)})
+
+	conflicts, err := tree.Merge(other)
+	assert.ErrorIs(t, err, radix.ErrConstraintConflict)
+	assert.Nil(t, conflicts)
+	assert.Equal(t, uint32(1), tree.Size())
+	assert.Len(t, tree.Get([]string{"about"}), 0, "A failed merge should add nothing")
+}
+
+func TestMergeJournal(t *testing.T) {
+	tree := radix.NewRadixTree().WithJournal()
+	tree.Add([]string{"users"}, "/users")
+	tree.Add([]string{"posts"}, "/posts")
+
+	other := radix.NewRadixTree()
+	other.Add([]string{"users"}, "/users")
+	other.AddOptional([]string{"posts", ":page"}, "/posts/:page")
+	other.Add([]string{"about"}, "/about")
+
+	conflicts, err := tree.Merge(other)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"posts", ":page"}, {"users"}}, conflicts, "An optional route should clash with a handler at its shorter path")
+	assert.Equal(t, uint32(3), tree.Size())
+
+	var journal bytes.Buffer
+	assert.Nil(t, tree.WriteJournal(&journal))
+	assert.Equal(t, 3, strings.Count(journal.String(), "\n"), "Skipped routes should not be journaled")
+	replayed, err := radix.Replay(&journal, func(path []string) radix.Handler { return "/" + strings.Join(path, "/") })
+	assert.Nil(t, err)
+	assert.True(t, tree.StructuralEqual(replayed, nil))
+}
@@ -0,0 +1,95 @@
+package radix
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type methodHandler struct {
+	method  string
+	handler Handler
+}
+
+// AddMethod registers handler for method at path. Handlers for different
+// methods coexist on the same path, and each counts as a route of its own.
+// Registering the same method twice on a path returns ErrHandlerExists.
+func (r *RadixTree) AddMethod(method string, path []string, handler Handler) error {
+	if method == "" {
+		return fmt.Errorf("method cannot be empty")
+	}
+	return r.update(func(root *Node) error {
+		if _, err := r.addRoute(root, path, assignMethod(method, handler), nil); err != nil {
+			return err
+		}
+		r.record(journalEntry{Op: journalAdd, Method: method, Path: path})
+		return nil
+	})
+}
+
+// DeleteMethod removes the handler registered for method at path with
+// AddMethod, leaving the handlers for other methods and any method-less
+// handler in place. It returns ErrPathNotFound if no handler is registered
+// for method at path.
+func (r *RadixTree) DeleteMethod(method string, path []string) error {
+	if method == "" {
+		return fmt.Errorf("method cannot be empty")
+	}
+	return r.update(func(root *Node) error {
+		if err := r.deleteRoute(root, method, path); err != nil {
+			return err
+		}
+		r.record(journalEntry{Op: journalDelete, Method: method, Path: path})
+		return nil
+	})
+}
+
+// GetMethod looks up path like Get, but only returns the handlers
+// registered for method. Routes registered without a method through Add
+// match every method. An empty method behaves like Get.
+func (r *RadixTree) GetMethod(method string, path []string) Routes {
+	var buf trailBuffer
+	return r.getValue(buf.start(r.root.Load()), path, nil, lookup{method: method})
+}
+
+func assignMethod(method string, handler Handler) func(*Node) error {
+	return func(node *Node) error {
+		if handler == nil {
+			return ErrNilHandler
+		}
+		i, found := slices.BinarySearchFunc(node.methods, method, compareMethod)
+		if found || node.optionalChild != nil {
+			return fmt.Errorf("%w: %s %q", ErrHandlerExists, method, node.path)
+		}
+		node.methods = slices.Insert(node.methods, i, methodHandler{method: method, handler: handler})
+		return nil
+	}
+}
+
+func (n *Node) methodHandler(method string) Handler {
+	if i, found := slices.BinarySearchFunc(n.methods, method, compareMethod); found {
+		return n.methods[i].handler
+	}
+	return nil
+}
+
+func compareMethod(mh methodHandler, method string) int {
+	return strings.Compare(mh.method, method)
+}
+
+// AllowedMethods returns the sorted methods registered with AddMethod on
+// the routes matching path, for building an Allow header. Method-less
+// handlers accept every method and are not listed. An unmatched path
+// returns an empty slice.
+func (r *RadixTree) AllowedMethods(path []string) []string {
+	methods := []string{}
+	for _, route := range r.Get(path) {
+		if route.Method == "" {
+			continue
+		}
+		if i, found := slices.BinarySearch(methods, route.Method); !found {
+			methods = slices.Insert(methods, i, route.Method)
+		}
+	}
+	return methods
+}
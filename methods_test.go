@@ -0,0 +1,137 @@
+package radix_test
+
+import (
+	"errors"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMethodRouting(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	assert.Nil(t, tree.AddMethod("GET", []string{"users"}, "list_users"))
+	assert.Nil(t, tree.AddMethod("POST", []string{"users"}, "create_user"))
+	assert.Nil(t, tree.AddMethod("GET", []string{"users", ":id"}, "show_user"))
+	assert.Equal(t, uint32(3), tree.Size())
+	assert.Equal(t, 3, tree.Count())
+
+	routes := tree.GetMethod("GET", []string{"users"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "list_users", routes[0].Handler.(string))
+	assert.Equal(t, "GET", routes[0].Method)
+
+	routes = tree.GetMethod("POST", []string{"users"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "create_user", routes[0].Handler.(string))
+	assert.Equal(t, "POST", routes[0].Method)
+
+	routes = tree.GetMethod("GET", []string{"users", "42"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, routes[0].Params)
+
+	assert.Len(t, tree.GetMethod("DELETE", []string{"users"}), 0)
+	assert.Len(t, tree.GetMethod("POST", []string{"users", "42"}), 0)
+}
+
+func TestMethodRoutingConflicts(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	assert.Nil(t, tree.AddMethod("GET", []string{"users"}, "list_users"))
+	err := tree.AddMethod("GET", []string{"users"}, "list_users_again")
+	assert.True(t, errors.Is(err, radix.ErrHandlerExists), "Same method and path should conflict")
+	assert.Equal(t, uint32(1), tree.Size())
+
+	_, err = tree.Add([]string{"users"}, "any_method")
+	assert.Nil(t, err, "A method-less handler should coexist with method handlers")
+
+	assert.NotNil(t, tree.AddMethod("", []string{"users"}, "empty"), "Empty method should be rejected")
+}
+
+func TestDeleteMethod(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"users"}, "any_method")
+	tree.AddMethod("GET", []string{"users"}, "list_users")
+	tree.AddMethod("POST", []string{"users"}, "create_user")
+	tree.AddMethod("GET", []string{"users", ":id"}, "show_user")
+
+	assert.Nil(t, tree.DeleteMethod("GET", []string{"users"}))
+	assert.Equal(t, uint32(3), tree.Size())
+	routes := tree.GetMethod("GET", []string{"users"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "any_method", routes[0].Handler.(string), "Other handlers on the path should stay")
+	routes = tree.GetMethod("POST", []string{"users"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "create_user", routes[0].Handler.(string))
+
+	err := tree.DeleteMethod("GET", []string{"users"})
+	assert.True(t, errors.Is(err, radix.ErrPathNotFound), "Deleting a method twice should fail")
+	assert.True(t, errors.Is(tree.DeleteMethod("PUT", []string{"missing"}), radix.ErrPathNotFound))
+	assert.NotNil(t, tree.DeleteMethod("", []string{"users"}), "Empty method should be rejected")
+
+	assert.Nil(t, tree.DeleteMethod("GET", []string{"users", ":id"}))
+	assert.Nil(t, tree.DeleteMethod("POST", []string{"users"}))
+	assert.Nil(t, tree.Delete([]string{"users"}))
+	assert.Zero(t, tree.Size())
+	assert.Empty(t, tree.Root().Children(), "Nodes left without routes should be pruned")
+}
+
+func TestGetWithoutMethod(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"users"}, "any_method")
+	tree.AddMethod("POST", []string{"users"}, "create_user")
+	tree.AddMethod("GET", []string{"users"}, "list_users")
+	tree.AddMethod("GET", []string{"files", "*filepath"}, "get_file")
+
+	routes := tree.Get([]string{"users"})
+	assert.Len(t, routes, 3, "Get should return every handler on the node")
+	assert.Equal(t, "any_method", routes[0].Handler.(string))
+	assert.Equal(t, "", routes[0].Method)
+	assert.Equal(t, "list_users", routes[1].Handler.(string))
+	assert.Equal(t, "GET", routes[1].Method)
+	assert.Equal(t, "create_user", routes[2].Handler.(string))
+	assert.Equal(t, "POST", routes[2].Method)
+
+	routes = tree.Get([]string{"files", "a", "b"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "get_file", routes[0].Handler.(string))
+	assert.Equal(t, radix.Params{{Key: "filepath", Values: []string{"a", "b"}}}, routes[0].Params)
+
+	routes = tree.GetMethod("DELETE", []string{"users"})
+	assert.Len(t, routes, 1, "Method-less handler should match any method")
+	assert.Equal(t, "any_method", routes[0].Handler.(string))
+	assert.Equal(t, "", routes[0].Method)
+}
+
+func TestWildcardMethodRouting(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	assert.Nil(t, tree.AddMethod("GET", []string{"files", "*filepath"}, "get_file"))
+	assert.Nil(t, tree.AddMethod("PUT", []string{"files", "*filepath"}, "put_file"))
+
+	routes := tree.GetMethod("PUT", []string{"files", "a"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "put_file", routes[0].Handler.(string))
+	assert.Equal(t, radix.Params{{Key: "filepath", Values: []string{"a"}}}, routes[0].Params)
+	assert.Len(t, tree.Get([]string{"files", "a"}), 2)
+}
+
+func TestAllowedMethods(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.AddMethod("POST", []string{"users"}, "create_user")
+	tree.AddMethod("GET", []string{"users"}, "list_users")
+	tree.AddMethod("GET", []string{"users", ":id"}, "show_user")
+	tree.AddMethod("DELETE", []string{"users", ":id"}, "delete_user")
+	tree.AddMethod("PUT", []string{"users", "*rest"}, "put_rest")
+	tree.Add([]string{"health"}, "health")
+
+	assert.Equal(t, []string{"GET", "POST"}, tree.AllowedMethods([]string{"users"}))
+	assert.Equal(t, []string{"DELETE", "GET", "PUT"}, tree.AllowedMethods([]string{"users", "42"}))
+	assert.Equal(t, []string{"PUT"}, tree.AllowedMethods([]string{"users", "42", "posts"}))
+	assert.Equal(t, []string{}, tree.AllowedMethods([]string{"health"}), "Method-less handlers should not be listed")
+	assert.Equal(t, []string{}, tree.AllowedMethods([]string{"missing"}))
+}
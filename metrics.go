@@ -0,0 +1,22 @@
+package radix
+
+// Metrics receives the outcome of every Get call.
+type Metrics interface {
+	OnMatch(path []string)
+	OnMiss(path []string)
+}
+
+// SetMetrics makes Get report each lookup to m. A nil m disables reporting.
+// It is not safe to call concurrently with lookups; set it up before the
+// tree is shared.
+func (r *RadixTree) SetMetrics(m Metrics) {
+	r.metrics = m
+}
+
+func (r *RadixTree) observe(path []string, routes Routes) {
+	if len(routes) > 0 {
+		r.metrics.OnMatch(path)
+	} else {
+		r.metrics.OnMiss(path)
+	}
+}
@@ -0,0 +1,40 @@
+package radix_test
+
+import (
+	"strings"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingMetrics struct {
+	matches, misses []string
+}
+
+func (m *countingMetrics) OnMatch(path []string) {
+	m.matches = append(m.matches, "/"+strings.Join(path, "/"))
+}
+
+func (m *countingMetrics) OnMiss(path []string) {
+	m.misses = append(m.misses, "/"+strings.Join(path, "/"))
+}
+
+func TestMetrics(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id"}, "user_show")
+
+	metrics := &countingMetrics{}
+	tree.SetMetrics(metrics)
+
+	tree.Get([]string{"users", "42"})
+	tree.Get([]string{"posts"})
+	tree.Get([]string{"users", "7"})
+
+	assert.Equal(t, []string{"/users/42", "/users/7"}, metrics.matches)
+	assert.Equal(t, []string{"/posts"}, metrics.misses)
+
+	tree.SetMetrics(nil)
+	tree.Get([]string{"posts"})
+	assert.Len(t, metrics.misses, 1, "Nil metrics should disable reporting")
+}
@@ -0,0 +1,48 @@
+package radix
+
+// SetNormalizer makes the tree fold static segments with fn, both when a
+// route is registered and when a lookup compares a path segment against
+// static children, so that, for example, percent-encoded or differently
+// composed forms of a segment match the same route. Static segments of
+// routes are stored in normalized form. Params and wildcards still capture
+// the segments as given, and constraints see them unchanged. fn must be
+// deterministic and idempotent, or routes may become unreachable; a nil fn
+// disables normalization. Set it before adding routes and, like
+// SetMetrics, before the tree is shared.
+func (r *RadixTree) SetNormalizer(fn func(segment string) string) {
+	r.normalizer = fn
+}
+
+// SetComparator makes lookups match a path segment against static children
+// with equal instead of the exact, normalized segment, passing the segment
+// a route registered and the one being looked up. The first static child in
+// segment order that equal accepts is followed. Routes are still registered
+// and found by Delete and the other writers under their exact segment.
+// Comparing scans the static children of every node on the way, where the
+// default is a single map lookup, so lookups slow down with the number of
+// siblings. A nil equal restores the default. Like SetNormalizer, set it
+// before the tree is shared.
+func (r *RadixTree) SetComparator(equal func(routeSeg, inputSeg string) bool) {
+	r.comparator = equal
+}
+
+// staticChild returns the static child of node matching the lookup segment.
+func (r *RadixTree) staticChild(node *Node, segment string) *Node {
+	segment = r.normalize(segment)
+	if r.comparator == nil {
+		return node.static_children[segment]
+	}
+	for _, key := range node.static_keys {
+		if r.comparator(key, segment) {
+			return node.static_children[key]
+		}
+	}
+	return nil
+}
+
+func (r *RadixTree) normalize(segment string) string {
+	if r.normalizer == nil {
+		return segment
+	}
+	return r.normalizer(segment)
+}
@@ -0,0 +1,90 @@
+package radix_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func unescape(segment string) string {
+	if unescaped, err := url.PathUnescape(segment); err == nil {
+		return unescaped
+	}
+	return segment
+}
+
+func TestSetNormalizer(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.SetNormalizer(strings.ToLower)
+
+	tree.Add([]string{"Users", ":id"}, "user")
+	tree.Add([]string{"about"}, "about")
+
+	routes := tree.Get([]string{"USERS", "Alice"})
+	assert.Equal(t, []string{"user"}, handlers(routes))
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"Alice"}}}, routes[0].Params, "Params should capture the segment as given")
+	assert.Equal(t, []string{"about"}, handlers(tree.Get([]string{"About"})))
+
+	_, err := tree.Add([]string{"ABOUT"}, "other")
+	assert.ErrorIs(t, err, radix.ErrHandlerExists, "Stored keys should be normalized")
+
+	_, _, found := tree.GetLeaf([]string{"users", "bob"})
+	assert.True(t, found)
+	_, _, found = tree.LongestPrefix([]string{"USERS", "bob", "extra"})
+	assert.True(t, found)
+	assert.True(t, tree.HasPrefix([]string{"USERS"}))
+
+	assert.Nil(t, tree.Delete([]string{"ABOUT"}))
+	assert.Empty(t, tree.Get([]string{"about"}))
+}
+
+func TestSetNormalizerStaticTree(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.SetNormalizer(unescape)
+
+	tree.Add([]string{"caf%C3%A9"}, "cafe")
+	assert.Equal(t, []string{"cafe"}, handlers(tree.Get([]string{"café"})))
+	assert.Equal(t, []string{"cafe"}, handlers(tree.GetBatch([][]string{{"caf%c3%a9"}})[0]))
+
+	tree.SetNormalizer(nil)
+	assert.Empty(t, tree.Get([]string{"caf%C3%A9"}), "Without a normalizer segments are compared as given")
+	assert.Equal(t, []string{"cafe"}, handlers(tree.Get([]string{"café"})))
+}
+
+func TestSetComparator(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.SetComparator(func(routeSeg, inputSeg string) bool {
+		return routeSeg == strings.TrimSpace(inputSeg)
+	})
+
+	tree.Add([]string{"api", ":id"}, "item")
+	tree.Add([]string{"about"}, "about")
+
+	assert.Equal(t, []string{"about"}, handlers(tree.Get([]string{" about "})))
+	routes := tree.Get([]string{"api ", " 42 "})
+	assert.Equal(t, []string{"item"}, handlers(routes))
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{" 42 "}}}, routes[0].Params, "Params capture the segment as given")
+	assert.Nil(t, tree.Get([]string{"contact"}))
+
+	_, err := tree.Add([]string{" about "}, "other")
+	assert.Nil(t, err, "Add keys by the exact segment")
+	assert.Equal(t, []string{"about"}, handlers(tree.Get([]string{" about "})), "The first matching segment in order wins")
+	assert.ErrorIs(t, tree.Delete([]string{"about "}), radix.ErrPathNotFound)
+
+	tree.SetComparator(nil)
+	assert.Nil(t, tree.Get([]string{"api ", "42"}))
+}
+
+func TestSetComparatorWithNormalizer(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.SetNormalizer(strings.ToLower)
+	tree.SetComparator(func(routeSeg, inputSeg string) bool {
+		return routeSeg == strings.TrimSuffix(inputSeg, ".html")
+	})
+	tree.Add([]string{"Docs"}, "docs")
+
+	assert.Equal(t, []string{"docs"}, handlers(tree.Get([]string{"DOCS.html"})), "Segments are normalized before comparing")
+}
@@ -0,0 +1,51 @@
+package radix
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// AddOptional registers handler at path, whose last segment must be a
+// param, and also makes it reachable without that segment. When the param
+// is absent, the route's Params hold it with defaultValues. The route counts
+// once in Size, is deleted through its full path, and conflicts with any
+// handler already registered at the shorter path.
+func (r *RadixTree) AddOptional(path []string, handler Handler, defaultValues ...string) (*NodeWrapper, error) {
+	var trail []*Node
+	err := r.update(func(root *Node) (err error) {
+		if trail, err = r.addOptional(root, path, assignHandler(handler), nil, defaultValues); err != nil {
+			return err
+		}
+		r.record(journalEntry{Op: journalAdd, Path: path, Optional: true, Defaults: defaultValues})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.live(trail), nil
+}
+
+// addOptional registers the optional route at path like addRoute and
+// returns the trail to its node.
+func (r *RadixTree) addOptional(root *Node, path []string, assign func(*Node) error, constraints map[string]*regexp.Regexp, defaultValues []string) ([]*Node, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("optional route needs a trailing param segment")
+	}
+	if nodeType, _ := r.classify(path[len(path)-1]); nodeType != ParamNode {
+		return nil, fmt.Errorf("only a trailing param segment can be optional: %q", path[len(path)-1])
+	}
+	if parent := r.findNode(root, path[:len(path)-1]); parent != nil {
+		if parent.handler != nil || len(parent.methods) > 0 || parent.optionalChild != nil {
+			return nil, fmt.Errorf("%w: %q", ErrHandlerExists, parent.path)
+		}
+	}
+	trail, err := r.addRoute(root, path, assign, constraints)
+	if err != nil {
+		return nil, err
+	}
+	node := trail[len(trail)-1]
+	node.defaultValues = slices.Clone(defaultValues)
+	trail[len(trail)-2].optionalChild = node
+	return trail, nil
+}
@@ -0,0 +1,84 @@
+package radix_test
+
+import (
+	"errors"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionalParam(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	_, err := tree.AddOptional([]string{"posts", ":page"}, "posts", "1")
+	assert.Nil(t, err)
+	tree.Add([]string{"posts", "latest"}, "latest")
+	assert.Equal(t, uint32(2), tree.Size())
+
+	routes := tree.Get([]string{"posts", "2"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "posts", routes[0].Handler.(string))
+	assert.Equal(t, radix.Params{{Key: "page", Values: []string{"2"}}}, routes[0].Params)
+
+	routes = tree.Get([]string{"posts"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "posts", routes[0].Handler.(string))
+	assert.Equal(t, radix.Params{{Key: "page", Values: []string{"1"}}}, routes[0].Params, "Missing param should use its default")
+
+	routes = tree.Get([]string{"posts", "latest"})
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "latest", routes[0].Handler.(string), "Static sibling should keep priority")
+
+	assert.Nil(t, tree.Delete([]string{"posts", ":page"}))
+	assert.Len(t, tree.Get([]string{"posts"}), 0)
+	assert.Len(t, tree.Get([]string{"posts", "2"}), 0)
+	assert.Equal(t, uint32(1), tree.Size())
+}
+
+func TestOptionalParamErrors(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	_, err := tree.AddOptional([]string{"posts", "all"}, "posts", "1")
+	assert.NotNil(t, err, "Only a param segment can be optional")
+
+	_, err = tree.AddOptional([]string{":page", "posts"}, "posts", "1")
+	assert.NotNil(t, err, "Only the last segment can be optional")
+
+	_, err = tree.AddOptional([]string{}, "root")
+	assert.NotNil(t, err)
+
+	tree.Add([]string{"users"}, "users")
+	_, err = tree.AddOptional([]string{"users", ":page"}, "users_page", "1")
+	assert.True(t, errors.Is(err, radix.ErrHandlerExists), "Optional param should not shadow an existing route")
+	assert.Len(t, tree.Get([]string{"users", "2"}), 0, "Failed registration should not add the route")
+
+	tree.AddOptional([]string{"posts", ":page"}, "posts", "1")
+	_, err = tree.Add([]string{"posts"}, "posts_index")
+	assert.True(t, errors.Is(err, radix.ErrHandlerExists), "Route at the shortened path should conflict")
+}
+
+func TestOptionalParamClone(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.AddOptional([]string{"posts", ":page"}, "posts", "1")
+
+	clone := tree.Clone()
+	assert.Nil(t, clone.Delete([]string{"posts", ":page"}))
+	assert.Len(t, clone.Get([]string{"posts"}), 0)
+	assert.Len(t, tree.Get([]string{"posts"}), 1, "Original should keep its optional route")
+}
+
+func TestOptionalParamDefaultsAreCopied(t *testing.T) {
+	tree := radix.NewRadixTree()
+	defaults := []string{"1"}
+	tree.AddOptional([]string{"posts", ":page"}, "posts", defaults...)
+	snapshot := tree.Snapshot()
+
+	defaults[0] = "changed"
+	assert.Equal(t, []string{"1"}, tree.Get([]string{"posts"})[0].Params[0].Values, "Changing the caller's slice should not change the defaults")
+	assert.Equal(t, []string{"1"}, snapshot.Get([]string{"posts"})[0].Params[0].Values)
+
+	routes := tree.Get([]string{"posts"})
+	routes[0].Params[0].Values[0] = "written"
+	assert.Equal(t, []string{"1"}, tree.Get([]string{"posts"})[0].Params[0].Values, "Writing a returned value should not change the tree")
+}
@@ -0,0 +1,194 @@
+package radix
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Options configures the route syntax understood by a RadixTree.
+type Options struct {
+	// ParamPrefix marks a parameter segment. Defaults to ':'.
+	ParamPrefix rune
+	// ParamSuffix, when set, must close a parameter segment, as in "{id}".
+	ParamSuffix rune
+	// WildcardPrefix marks a catch-all segment. Defaults to '*'. A catch-all
+	// matches one or more segments; doubling its markers, as in "**path",
+	// makes it match zero or more.
+	WildcardPrefix rune
+	// WildcardSuffix, when set, must close a catch-all segment, as in "[path]".
+	WildcardSuffix rune
+	// StrictWildcards makes Add reject routes that would give a node more
+	// than one wildcard child, or both a wildcard and a param child, since
+	// such siblings match the same segments.
+	StrictWildcards bool
+	// StrictChildren makes Add reject routes that would give a node both
+	// static and param children, such as "/users/id" next to "/users/:id",
+	// since the param also matches the static segment.
+	StrictChildren bool
+	// StrictNames makes Add reject param and wildcard segments that declare
+	// no name, such as ":" or "*". Unnamed segments all share the empty
+	// key, so their values cannot be told apart in Params.
+	StrictNames bool
+	// UniqueParams makes Add reject routes that capture the same param or
+	// wildcard name more than once, such as "/a/:x/:x", whose values would
+	// share a key in Params.
+	UniqueParams bool
+	// PreferStatic makes lookups stop at a static match: when the static
+	// child for a segment leads to a route, its param and wildcard siblings
+	// are not explored.
+	PreferStatic bool
+	// MaxParams, when positive, bounds the params a lookup may capture:
+	// branches that would capture more are not explored.
+	MaxParams int
+	// PriorityOrder lists the order in which lookups try the static, param
+	// and wildcard children of a node, and so the order of their routes in
+	// Get. It must hold each NodeType exactly once; nil means
+	// [Static, ParamNode, Wildcard]. PreferStatic still stops at a static
+	// match, skipping only the kinds ordered after it. Routes.Sort always
+	// uses the default order.
+	PriorityOrder []NodeType
+	// DecodeParams makes lookups percent-decode the values captured by
+	// params and wildcards, segment by segment, keeping a segment as given
+	// when it is not validly encoded. Constraints still see the raw text,
+	// and static segments are compared as given unless SetNormalizer folds
+	// them.
+	DecodeParams bool
+	// MaxRoutes, when positive, caps Size: a write that would leave the
+	// tree with more routes fails with ErrTooManyRoutes and changes
+	// nothing.
+	MaxRoutes uint32
+	// RawWildcards makes lookups also set RouteParam.Raw for wildcards, to
+	// the captured segments joined with "/" before any DecodeParams
+	// decoding, saving handlers such as file servers from joining Values.
+	RawWildcards bool
+}
+
+var defaultPriorityOrder = []NodeType{Static, ParamNode, Wildcard}
+
+// NewRadixTreeWithOptions returns an empty tree configured by opts. It
+// panics if opts.PriorityOrder is not a valid order.
+func NewRadixTreeWithOptions(opts Options) *RadixTree {
+	if opts.ParamPrefix == 0 {
+		opts.ParamPrefix = ':'
+	}
+	if opts.WildcardPrefix == 0 {
+		opts.WildcardPrefix = '*'
+	}
+	if opts.PriorityOrder == nil {
+		opts.PriorityOrder = defaultPriorityOrder
+	} else if !validPriorityOrder(opts.PriorityOrder) {
+		panic(fmt.Sprintf("radix: PriorityOrder must list each node type once: %v", opts.PriorityOrder))
+	}
+	opts.PriorityOrder = slices.Clone(opts.PriorityOrder)
+	r := &RadixTree{opts: opts}
+	r.root.Store(&Node{})
+	return r
+}
+
+// validPriorityOrder reports whether order is a permutation of the node types.
+func validPriorityOrder(order []NodeType) bool {
+	if len(order) != len(defaultPriorityOrder) {
+		return false
+	}
+	for _, kind := range defaultPriorityOrder {
+		if !slices.Contains(order, kind) {
+			return false
+		}
+	}
+	return true
+}
+
+// escapePrefix forces the rest of a route segment to be static, so that
+// `\:443` registers the literal segment ":443".
+const escapePrefix = `\`
+
+// classify reports the kind of a route segment and the name it declares for
+// params and wildcards, or the normalized literal text it matches for static
+// segments.
+func (r *RadixTree) classify(segment string) (NodeType, string) {
+	if literal, ok := strings.CutPrefix(segment, escapePrefix); ok && r.needsEscape(literal) {
+		return Static, r.normalize(literal)
+	}
+	if name, ok := trimMarkers(segment, r.opts.WildcardPrefix, r.opts.WildcardSuffix); ok {
+		if inner, ok := trimMarkers(name, r.opts.WildcardPrefix, r.opts.WildcardSuffix); ok {
+			return Wildcard, inner
+		}
+		return Wildcard, name
+	}
+	if name, ok := trimMarkers(segment, r.opts.ParamPrefix, r.opts.ParamSuffix); ok {
+		return ParamNode, name
+	}
+	return Static, r.normalize(segment)
+}
+
+// needsEscape reports whether the static segment literal would be read as
+// something else if registered without an escape.
+func (r *RadixTree) needsEscape(literal string) bool {
+	return strings.HasPrefix(literal, escapePrefix) ||
+		strings.HasPrefix(literal, string(r.opts.ParamPrefix)) ||
+		strings.HasPrefix(literal, string(r.opts.WildcardPrefix))
+}
+
+// paramSegment returns the route segment declaring the param name.
+func (r *RadixTree) paramSegment(name string) string {
+	return withMarkers(name, r.opts.ParamPrefix, r.opts.ParamSuffix)
+}
+
+// wildcardSegment returns the route segment declaring the wildcard name.
+func (r *RadixTree) wildcardSegment(name string) string {
+	return withMarkers(name, r.opts.WildcardPrefix, r.opts.WildcardSuffix)
+}
+
+func withMarkers(name string, prefix, suffix rune) string {
+	segment := string(prefix) + name
+	if suffix != 0 {
+		segment += string(suffix)
+	}
+	return segment
+}
+
+// escape returns the route segment registering the static segment literal.
+func (r *RadixTree) escape(literal string) string {
+	if r.needsEscape(literal) {
+		return escapePrefix + literal
+	}
+	return literal
+}
+
+// matchesEmpty reports whether a wildcard segment uses the doubled marker,
+// as in "**path", which also matches when no segments are left.
+func (r *RadixTree) matchesEmpty(segment string) bool {
+	name, _ := trimMarkers(segment, r.opts.WildcardPrefix, r.opts.WildcardSuffix)
+	_, ok := trimMarkers(name, r.opts.WildcardPrefix, r.opts.WildcardSuffix)
+	return ok
+}
+
+func trimMarkers(segment string, prefix, suffix rune) (string, bool) {
+	name, ok := strings.CutPrefix(segment, string(prefix))
+	if !ok {
+		return "", false
+	}
+	if suffix != 0 {
+		return strings.CutSuffix(name, string(suffix))
+	}
+	return name, true
+}
+
+// paramValues returns the values a param or wildcard captures from segments,
+// decoded when DecodeParams is set.
+func (r *RadixTree) paramValues(segments []string) []string {
+	if !r.opts.DecodeParams {
+		return segments
+	}
+	values := make([]string, len(segments))
+	for i, segment := range segments {
+		if decoded, err := url.PathUnescape(segment); err == nil {
+			values[i] = decoded
+		} else {
+			values[i] = segment
+		}
+	}
+	return values
+}
@@ -0,0 +1,425 @@
+package radix_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomPrefixes(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{
+		ParamPrefix:    '{',
+		ParamSuffix:    '}',
+		WildcardPrefix: '[',
+		WildcardSuffix: ']',
+	})
+
+	tree.Add([]string{"users", "{id}"}, "user_show")
+	tree.Add([]string{"files", "[filepath]"}, "files")
+	tree.Add([]string{"config", ":443"}, "literal_colon")
+	tree.Add([]string{"stars", "*"}, "literal_star")
+
+	routes := tree.Get([]string{"users", "123"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"123"}}}, routes[0].Params)
+
+	routes = tree.Get([]string{"files", "a", "b"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{{Key: "filepath", Values: []string{"a", "b"}}}, routes[0].Params)
+
+	routes = tree.Get([]string{"config", ":443"})
+	assert.Len(t, routes, 1, "Default markers should be static under custom options")
+	assert.Nil(t, routes[0].Params)
+	assert.Len(t, tree.Get([]string{"config", "8080"}), 0)
+
+	routes = tree.Get([]string{"stars", "*"})
+	assert.Len(t, routes, 1)
+	assert.Len(t, tree.Get([]string{"stars", "a"}), 0)
+
+	assert.Nil(t, tree.Delete([]string{"users", "{id}"}))
+	assert.Nil(t, tree.Delete([]string{"files", "[filepath]"}))
+	assert.Len(t, tree.Get([]string{"users", "123"}), 0)
+	assert.Len(t, tree.Get([]string{"files", "a", "b"}), 0)
+	assert.Equal(t, uint32(2), tree.Size())
+}
+
+func TestCustomPrefixMissingSuffix(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{ParamPrefix: '{', ParamSuffix: '}'})
+
+	tree.Add([]string{"users", "{id"}, "unterminated")
+
+	assert.Len(t, tree.Get([]string{"users", "123"}), 0, "Unterminated param should be treated as static")
+	assert.Len(t, tree.Get([]string{"users", "{id"}), 1)
+}
+
+func TestDefaultOptions(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{})
+
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"files", "*filepath"}, "files")
+
+	routes := tree.Get([]string{"users", "123"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"123"}}}, routes[0].Params)
+
+	routes = tree.Get([]string{"files", "a"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{{Key: "filepath", Values: []string{"a"}}}, routes[0].Params)
+}
+
+func TestStrictWildcards(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{StrictWildcards: true})
+
+	_, err := tree.Add([]string{"files", "*filepath"}, "files")
+	assert.Nil(t, err)
+
+	_, err = tree.Add([]string{"files", "*other"}, "other")
+	assert.ErrorIs(t, err, radix.ErrAmbiguousRoute, "Second wildcard child should be rejected")
+	assert.ErrorContains(t, err, `"/files/*other" conflicts with "/files/*filepath"`)
+
+	_, err = tree.Add([]string{"files", "*filepath"}, "again")
+	assert.ErrorIs(t, err, radix.ErrAmbiguousRoute, "Duplicate wildcard should be rejected")
+
+	_, err = tree.Add([]string{"files", ":name", "meta"}, "meta")
+	assert.ErrorIs(t, err, radix.ErrAmbiguousRoute, "Param next to a wildcard should be rejected")
+	assert.ErrorContains(t, err, `"/files/:name" conflicts with "/files/*filepath"`)
+
+	_, err = tree.Add([]string{"users", ":id"}, "user")
+	assert.Nil(t, err)
+	_, err = tree.Add([]string{"users", "*rest"}, "rest")
+	assert.ErrorIs(t, err, radix.ErrAmbiguousRoute, "Wildcard next to a param should be rejected")
+	assert.ErrorContains(t, err, `"/users/*rest" conflicts with "/users/:id"`)
+
+	_, err = tree.Add([]string{"users", ":id", "posts"}, "posts")
+	assert.Nil(t, err, "Reusing an existing param node should be allowed")
+	_, err = tree.Add([]string{"files", "static"}, "static")
+	assert.Nil(t, err, "Static siblings are never ambiguous")
+
+	assert.Equal(t, uint32(4), tree.Size())
+}
+
+func TestStrictWildcardsDisabledByDefault(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"files", "*filepath"}, "files")
+	_, err := tree.Add([]string{"files", "*other"}, "other")
+	assert.Nil(t, err)
+	_, err = tree.Add([]string{"files", ":name"}, "name")
+	assert.Nil(t, err)
+}
+
+func TestStrictChildren(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{StrictChildren: true})
+
+	_, err := tree.Add([]string{"users", "id"}, "static")
+	assert.Nil(t, err)
+	_, err = tree.Add([]string{"users", ":id"}, "param")
+	assert.ErrorIs(t, err, radix.ErrAmbiguousRoute)
+	assert.ErrorContains(t, err, `param "/users/:id" conflicts with static "/users/id"`)
+
+	_, err = tree.Add([]string{"posts", ":id"}, "param")
+	assert.Nil(t, err)
+	_, err = tree.Add([]string{"posts", "new"}, "static")
+	assert.ErrorIs(t, err, radix.ErrAmbiguousRoute)
+	assert.ErrorContains(t, err, `static "/posts/new" conflicts with param "/posts/:id"`)
+
+	_, err = tree.Add([]string{"posts", ":id", "edit"}, "edit")
+	assert.Nil(t, err, "Reusing an existing param node should be allowed")
+	_, err = tree.Add([]string{"users", "me"}, "me")
+	assert.Nil(t, err, "Static siblings are never ambiguous")
+	_, err = tree.Add([]string{"users", "*rest"}, "rest")
+	assert.Nil(t, err, "Wildcards are only lower-priority fallbacks")
+
+	assert.Equal(t, uint32(5), tree.Size())
+}
+
+func TestStrictChildrenDisabledByDefault(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"users", "id"}, "static")
+	_, err := tree.Add([]string{"users", ":id"}, "param")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"static", "param"}, handlers(tree.Get([]string{"users", "id"})))
+}
+
+func TestStrictNames(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{StrictNames: true})
+
+	_, err := tree.Add([]string{"users", ":"}, "handler")
+	assert.ErrorIs(t, err, radix.ErrEmptyName)
+	assert.ErrorContains(t, err, `":"`)
+
+	_, err = tree.Add([]string{"files", "*"}, "handler")
+	assert.ErrorIs(t, err, radix.ErrEmptyName)
+
+	_, err = tree.Add([]string{"users", ":id"}, "handler")
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), tree.Size(), "Rejected routes should not be counted")
+
+	braces := radix.NewRadixTreeWithOptions(radix.Options{ParamPrefix: '{', ParamSuffix: '}', StrictNames: true})
+	_, err = braces.Add([]string{"users", "{}"}, "handler")
+	assert.ErrorIs(t, err, radix.ErrEmptyName)
+
+	// Unnamed params share the "" key, so without StrictNames a second one
+	// at the same level lands on the first one's node.
+	lenient := radix.NewRadixTree()
+	_, err = lenient.Add([]string{"posts", ":"}, "first")
+	assert.Nil(t, err)
+	_, err = lenient.Add([]string{"posts", ":", "edit"}, "edit")
+	assert.Nil(t, err)
+	_, err = lenient.Add([]string{"posts", ":"}, "second")
+	assert.ErrorIs(t, err, radix.ErrHandlerExists, "Unnamed params should collide on the empty key")
+	routes := lenient.Get([]string{"posts", "42"})
+	assert.Equal(t, []string{"first"}, handlers(routes))
+	assert.Equal(t, radix.Params{{Key: "", Values: []string{"42"}}}, routes[0].Params)
+
+	_, err = tree.Add([]string{"posts", ":", "edit"}, "edit")
+	assert.ErrorIs(t, err, radix.ErrEmptyName, "StrictNames should reject the colliding params")
+}
+
+func TestUniqueParams(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{UniqueParams: true})
+
+	_, err := tree.Add([]string{"a", ":x", ":x"}, "handler")
+	assert.ErrorIs(t, err, radix.ErrDuplicateParam)
+	assert.ErrorContains(t, err, `"x" in [a :x :x]`)
+
+	_, err = tree.Add([]string{"a", ":x", "b", "*x"}, "handler")
+	assert.ErrorIs(t, err, radix.ErrDuplicateParam, "Wildcards share the param namespace")
+
+	_, err = tree.Add([]string{"a", ":x", ":y"}, "handler")
+	assert.Nil(t, err)
+	_, err = tree.Add([]string{"b", ":x"}, "handler")
+	assert.Nil(t, err, "The same name on separate paths is fine")
+	assert.Equal(t, uint32(2), tree.Size(), "Rejected routes should not be counted")
+
+	lenient := radix.NewRadixTree()
+	_, err = lenient.Add([]string{"a", ":x", ":x"}, "handler")
+	assert.Nil(t, err)
+}
+
+func TestEmptyNamesCollide(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	_, err := tree.Add([]string{"users", ":"}, "first")
+	assert.Nil(t, err)
+	_, err = tree.Add([]string{"users", ":", "posts"}, "posts")
+	assert.Nil(t, err)
+
+	routes := tree.Get([]string{"users", "42"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{{Key: "", Values: []string{"42"}}}, routes[0].Params)
+
+	_, err = tree.Add([]string{"users", ":"}, "second")
+	assert.ErrorIs(t, err, radix.ErrHandlerExists, "Unnamed params share a single node")
+}
+
+func TestPreferStatic(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{PreferStatic: true})
+
+	tree.Add([]string{"users", "new"}, "user_new")
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"users", "*rest"}, "user_rest")
+	tree.Add([]string{"files", "static", ":name"}, "static_file")
+	tree.Add([]string{"files", ":dir", "index"}, "dir_index")
+
+	routes := tree.Get([]string{"users", "new"})
+	assert.Len(t, routes, 1, "Static match should suppress param and wildcard siblings")
+	assert.Equal(t, "user_new", routes[0].Handler.(string))
+
+	routes = tree.Get([]string{"users", "42"})
+	assert.Len(t, routes, 2, "Without a static match siblings are still explored")
+	assert.Equal(t, "user_show", routes[0].Handler.(string))
+
+	routes = tree.Get([]string{"files", "static", "index"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "static_file", routes[0].Handler.(string))
+
+	routes = tree.Get([]string{"files", "static", "index", "more"})
+	assert.Len(t, routes, 0)
+
+	tree.Add([]string{"docs", "guide", "intro"}, "intro")
+	tree.Add([]string{"docs", ":section", "toc"}, "toc")
+	routes = tree.Get([]string{"docs", "guide", "toc"})
+	assert.Len(t, routes, 1, "A static child without a match should not suppress siblings")
+	assert.Equal(t, "toc", routes[0].Handler.(string))
+}
+
+func TestMaxParams(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{MaxParams: 2})
+
+	tree.Add([]string{":a", ":b", ":c"}, "three")
+	tree.Add([]string{":a", ":b"}, "two")
+	tree.Add([]string{"x", ":b", "*rest"}, "wildcard")
+	tree.Add([]string{"x", "y", "z"}, "static")
+
+	assert.Len(t, tree.Get([]string{"1", "2", "3"}), 0, "Branch with too many params should not match")
+
+	routes := tree.Get([]string{"1", "2"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "two", routes[0].Handler.(string))
+
+	routes = tree.Get([]string{"x", "y", "z"})
+	assert.Len(t, routes, 2, "Routes within the limit still match")
+	assert.Equal(t, "static", routes[0].Handler.(string))
+	assert.Equal(t, "wildcard", routes[1].Handler.(string))
+
+	tree.Add([]string{"x", ":b", ":c", "*rest"}, "too_many")
+	assert.Len(t, tree.Get([]string{"x", "y", "z", "w"}), 1)
+
+	unlimited := radix.NewRadixTree()
+	unlimited.Add([]string{":a", ":b", ":c"}, "three")
+	assert.Len(t, unlimited.Get([]string{"1", "2", "3"}), 1)
+}
+
+func TestPriorityOrder(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{
+		PriorityOrder: []radix.NodeType{radix.ParamNode, radix.Static, radix.Wildcard},
+	})
+	tree.Add([]string{":lang", "docs"}, "lang")
+	tree.Add([]string{"api", "docs"}, "api")
+	tree.Add([]string{"*rest"}, "rest")
+
+	assert.Equal(t, []string{"lang", "api", "rest"}, handlers(tree.Get([]string{"api", "docs"})))
+	route, found := tree.GetOne([]string{"api", "docs"}, nil)
+	assert.True(t, found)
+	assert.Equal(t, "lang", route.Handler.(string))
+	assert.Equal(t, []string{"lang"}, handlers(tree.GetN([]string{"api", "docs"}, 1)))
+
+	reversed := radix.NewRadixTreeWithOptions(radix.Options{
+		PriorityOrder: []radix.NodeType{radix.Wildcard, radix.ParamNode, radix.Static},
+	})
+	reversed.Add([]string{"files", "**path"}, "path")
+	reversed.AddOptional([]string{"files", ":name"}, "name", "index")
+	assert.Equal(t, []string{"path", "name"}, handlers(reversed.Get([]string{"files"})))
+}
+
+func TestPriorityOrderPreferStatic(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{
+		PriorityOrder: []radix.NodeType{radix.ParamNode, radix.Static, radix.Wildcard},
+		PreferStatic:  true,
+	})
+	tree.Add([]string{":lang"}, "lang")
+	tree.Add([]string{"api"}, "api")
+	tree.Add([]string{"*rest"}, "rest")
+
+	assert.Equal(t, []string{"lang", "api"}, handlers(tree.Get([]string{"api"})))
+}
+
+func TestPriorityOrderDefault(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{})
+	tree.Add([]string{":lang"}, "lang")
+	tree.Add([]string{"api"}, "api")
+	tree.Add([]string{"*rest"}, "rest")
+
+	assert.Equal(t, []string{"api", "lang", "rest"}, handlers(tree.Get([]string{"api"})))
+}
+
+func TestPriorityOrderInvalid(t *testing.T) {
+	for _, order := range [][]radix.NodeType{
+		{},
+		{radix.Static, radix.ParamNode},
+		{radix.Static, radix.Static, radix.Wildcard},
+		{radix.Static, radix.ParamNode, radix.Wildcard, radix.Static},
+	} {
+		assert.Panics(t, func() {
+			radix.NewRadixTreeWithOptions(radix.Options{PriorityOrder: order})
+		}, "%v", order)
+	}
+}
+
+func TestDecodeParams(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{DecodeParams: true})
+	tree.Add([]string{"files", ":name"}, "file")
+	tree.Add([]string{"static", "*path"}, "static")
+	tree.Add([]string{"my%20dir", ":name"}, "encoded")
+
+	routes := tree.Get([]string{"files", "my%20file"})
+	assert.Equal(t, radix.Params{{Key: "name", Values: []string{"my file"}}}, routes[0].Params)
+
+	routes = tree.Get([]string{"files", "a%2Fb"})
+	assert.Equal(t, radix.Params{{Key: "name", Values: []string{"a/b"}}}, routes[0].Params, "An encoded slash stays within one value")
+
+	routes = tree.Get([]string{"static", "css%20files", "site%2Emin.css"})
+	assert.Equal(t, radix.Params{{Key: "path", Values: []string{"css files", "site.min.css"}}}, routes[0].Params)
+
+	routes = tree.Get([]string{"files", "100%"})
+	assert.Equal(t, radix.Params{{Key: "name", Values: []string{"100%"}}}, routes[0].Params, "Invalid encodings are kept as given")
+
+	assert.Len(t, tree.Get([]string{"my%20dir", "x"}), 1, "Static segments match the raw text")
+	assert.Empty(t, tree.Get([]string{"my dir", "x"}))
+
+	route, _, _ := tree.LongestPrefix([]string{"files", "my%20file", "extra"})
+	assert.Equal(t, radix.Params{{Key: "name", Values: []string{"my file"}}}, route.Params)
+
+	raw := radix.NewRadixTree()
+	raw.Add([]string{"files", ":name"}, "file")
+	routes = raw.Get([]string{"files", "my%20file"})
+	assert.Equal(t, radix.Params{{Key: "name", Values: []string{"my%20file"}}}, routes[0].Params)
+}
+
+func TestMaxRoutes(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{MaxRoutes: 3}).WithJournal()
+
+	for _, path := range [][]string{{"a"}, {"b", ":id"}} {
+		_, err := tree.Add(path, "handler")
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tree.AddMethod("GET", []string{"a"}, "get"))
+
+	_, err := tree.Add([]string{"c"}, "handler")
+	assert.ErrorIs(t, err, radix.ErrTooManyRoutes)
+	assert.Empty(t, tree.Get([]string{"c"}), "The rejected route should not be present")
+	assert.Equal(t, uint32(3), tree.Size())
+	assert.Equal(t, 3, tree.Count())
+	assert.ErrorIs(t, tree.CanAdd([]string{"c"}), radix.ErrTooManyRoutes)
+
+	err = tree.AddBatch([]radix.BatchRoute{{Path: []string{"d"}, Handler: "d"}})
+	assert.ErrorIs(t, err, radix.ErrTooManyRoutes)
+
+	var buf bytes.Buffer
+	assert.Nil(t, tree.WriteJournal(&buf))
+	assert.Equal(t, 3, strings.Count(buf.String(), "\n"), "Rejected writes should not be journaled")
+
+	assert.Nil(t, tree.Delete([]string{"a"}))
+	_, err = tree.Add([]string{"c"}, "handler")
+	assert.Nil(t, err, "Deleting should make room again")
+}
+
+func TestMaxRoutesUnlimitedByDefault(t *testing.T) {
+	tree := radix.NewRadixTree()
+	for i := range 100 {
+		_, err := tree.Add([]string{fmt.Sprint(i)}, i)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, uint32(100), tree.Size())
+}
+
+func TestRawWildcards(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{RawWildcards: true, DecodeParams: true})
+	tree.Add([]string{"static", "*path"}, "static")
+	tree.Add([]string{"docs", "**page"}, "docs")
+	tree.Add([]string{"users", ":id"}, "user")
+
+	routes := tree.Get([]string{"static", "css", "my%20site.css"})
+	assert.Equal(t, radix.Params{{
+		Key:    "path",
+		Values: []string{"css", "my site.css"},
+		Raw:    "css/my%20site.css",
+	}}, routes[0].Params)
+
+	routes = tree.Get([]string{"docs"})
+	assert.Equal(t, radix.Params{{Key: "page", Values: []string{}}}, routes[0].Params)
+
+	routes = tree.Get([]string{"users", "42"})
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, routes[0].Params, "Params do not get a raw form")
+
+	plain := radix.NewRadixTree()
+	plain.Add([]string{"static", "*path"}, "static")
+	routes = plain.Get([]string{"static", "a", "b"})
+	assert.Equal(t, radix.Params{{Key: "path", Values: []string{"a", "b"}}}, routes[0].Params, "Raw is only set on request")
+}
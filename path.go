@@ -0,0 +1,46 @@
+package radix
+
+import (
+	"net/url"
+	"strings"
+)
+
+// ParsePath splits a URL path into the segments expected by Add and Get.
+// A single leading "/" is stripped and a single trailing "/" is dropped, so
+// "/api/v1/" becomes ["api", "v1"] and "/" becomes []. Consecutive slashes
+// are kept as empty segments: "//a" becomes ["", "a"] and "/a//b" becomes
+// ["a", "", "b"].
+func ParsePath(s string) []string {
+	s = strings.TrimPrefix(s, "/")
+	s = strings.TrimSuffix(s, "/")
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, "/")
+}
+
+// GetWithTrailingSlash looks up path like Get. When nothing matches and path
+// ends with an empty segment (a trailing slash), it retries without that
+// segment; the returned bool reports whether that retry produced the match,
+// so callers can redirect to the canonical path.
+func (r *RadixTree) GetWithTrailingSlash(path []string) (Routes, bool) {
+	routes := r.Get(path)
+	if len(routes) > 0 || len(path) == 0 || path[len(path)-1] != "" {
+		return routes, false
+	}
+	routes = r.Get(path[:len(path)-1])
+	return routes, len(routes) > 0
+}
+
+// GetURL looks up the path of rawurl like Get, ignoring its scheme, host,
+// query and fragment. The path is split in its escaped form, so an encoded
+// "%2F" stays within its segment and segments reach the tree as sent; use
+// DecodeParams to have captured values decoded. It returns an error if
+// rawurl cannot be parsed.
+func (r *RadixTree) GetURL(rawurl string) (Routes, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(ParsePath(u.EscapedPath())), nil
+}
@@ -0,0 +1,134 @@
+package radix_test
+
+import (
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"", []string{}},
+		{"/", []string{}},
+		{"/api", []string{"api"}},
+		{"/api/v1", []string{"api", "v1"}},
+		{"/api/v1/", []string{"api", "v1"}},
+		{"api/v1", []string{"api", "v1"}},
+		{"//a", []string{"", "a"}},
+		{"/a//b", []string{"a", "", "b"}},
+		{"/files/~//:name", []string{"files", "~", "", ":name"}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, radix.ParsePath(test.path), "ParsePath(%q)", test.path)
+	}
+}
+
+func TestParsePathWithTree(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add(radix.ParsePath("/"), "root")
+	tree.Add(radix.ParsePath("/users/:id"), "user_show")
+
+	routes := tree.Get(radix.ParsePath("/"))
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "root", routes[0].Handler.(string))
+
+	routes = tree.Get(radix.ParsePath("/users/123/"))
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "user_show", routes[0].Handler.(string))
+}
+
+func TestGetWithTrailingSlash(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users"}, "users")
+	tree.Add([]string{"docs", ""}, "docs_slash")
+
+	routes, redirect := tree.GetWithTrailingSlash([]string{"users"})
+	assert.Len(t, routes, 1)
+	assert.False(t, redirect, "Exact match should not require a redirect")
+
+	routes, redirect = tree.GetWithTrailingSlash([]string{"users", ""})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "users", routes[0].Handler.(string))
+	assert.True(t, redirect, "Match after trimming the trailing slash should be reported")
+
+	routes, redirect = tree.GetWithTrailingSlash([]string{"docs", ""})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "docs_slash", routes[0].Handler.(string))
+	assert.False(t, redirect, "Registered trailing-slash route should match as-is")
+
+	routes, redirect = tree.GetWithTrailingSlash([]string{"admin", ""})
+	assert.Len(t, routes, 0)
+	assert.False(t, redirect)
+
+	assert.Len(t, tree.Get([]string{"users", ""}), 0, "Get should not trim trailing slashes")
+}
+
+func TestEscapedLiteralSegments(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	_, err := tree.Add([]string{"config", `\:443`}, "port")
+	assert.Nil(t, err)
+	tree.Add([]string{"config", ":key"}, "key")
+	tree.Add([]string{"glob", `\*`}, "star")
+	tree.Add([]string{"raw", `\\n`}, "backslash")
+
+	routes := tree.Get([]string{"config", ":443"})
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "port", routes[0].Handler.(string), "Escaped segment should match as static")
+	assert.Empty(t, routes[0].Params)
+	assert.Equal(t, "key", routes[1].Handler.(string))
+
+	routes = tree.Get([]string{"config", "443"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "key", routes[0].Handler.(string))
+
+	assert.Len(t, tree.Get([]string{"glob", "*"}), 1)
+	assert.Len(t, tree.Get([]string{"glob", "a"}), 0, "Escaped wildcard marker should not catch all")
+	assert.Len(t, tree.Get([]string{"raw", `\n`}), 1)
+
+	// Walk escapes literals again so routes can be re-registered as is.
+	copied := radix.NewRadixTree()
+	tree.Walk(func(path []string, handler radix.Handler) bool {
+		_, err := copied.Add(path, handler)
+		assert.Nil(t, err)
+		return true
+	})
+	assert.Len(t, copied.Get([]string{"config", ":443"}), 2)
+	assert.Len(t, copied.Get([]string{"glob", "a"}), 0)
+	assert.Len(t, copied.Get([]string{"raw", `\n`}), 1)
+
+	assert.Nil(t, tree.Delete([]string{"config", `\:443`}))
+	routes = tree.Get([]string{"config", ":443"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "key", routes[0].Handler.(string))
+}
+
+func TestGetURL(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{DecodeParams: true})
+	tree.Add([]string{"files", ":name"}, "file")
+	tree.Add([]string{"search"}, "search")
+
+	routes, err := tree.GetURL("https://example.com/search?q=radix#results")
+	assert.Nil(t, err)
+	assert.Len(t, routes, 1)
+
+	routes, err = tree.GetURL("/files/my%20file?download=1")
+	assert.Nil(t, err)
+	assert.Equal(t, radix.Params{{Key: "name", Values: []string{"my file"}}}, routes[0].Params)
+
+	routes, err = tree.GetURL("/files/a%2Fb")
+	assert.Nil(t, err)
+	assert.Equal(t, radix.Params{{Key: "name", Values: []string{"a/b"}}}, routes[0].Params, "An encoded slash should not split the segment")
+
+	routes, err = tree.GetURL("/missing")
+	assert.Nil(t, err)
+	assert.Empty(t, routes)
+
+	_, err = tree.GetURL("http://[::1/files/x")
+	assert.Error(t, err)
+}
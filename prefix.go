@@ -0,0 +1,127 @@
+package radix
+
+// LongestPrefix descends from the root along path, following the static
+// child for each segment or, failing that, a matching param child, and
+// returns the route of the deepest node with a handler seen on the way
+// together with the segments left unconsumed below it. Wildcards are not
+// followed.
+func (r *RadixTree) LongestPrefix(path []string) (Route, []string, bool) {
+	node := r.root.Load()
+	var buf trailBuffer
+	trail := buf.start(node)
+	var params Params
+	var best Routes
+	rest := path
+
+	for i := 0; ; i++ {
+		if routes := (lookup{}).appendRoutes(nil, trail, params); len(routes) > 0 {
+			best, rest = routes, path[i:]
+		}
+		if i == len(path) {
+			break
+		}
+		segment := path[i]
+		child := r.staticChild(node, segment)
+		if child == nil {
+			if child = node.paramChild(segment); child == nil {
+				break
+			}
+			params = append(params[:len(params):len(params)], RouteParam{
+				Key:    child.paramName,
+				Values: r.paramValues(path[i : i+1]),
+			})
+		}
+		node = child
+		trail = append(trail, node)
+	}
+
+	if len(best) == 0 {
+		return Route{}, path, false
+	}
+	return best[0], rest, true
+}
+
+// MatchPrefix returns the route of the deepest node with a handler that a
+// prefix of path leads to through static and param children, with the
+// segments left over below it. Unlike LongestPrefix, which follows a single
+// branch, every matching branch is explored, so a deeper handler behind a
+// param wins over a shallower one behind a static segment. Among equally
+// deep matches the usual priority applies. Wildcards are not followed.
+func (r *RadixTree) MatchPrefix(path []string) (Route, []string, bool) {
+	var best Route
+	bestDepth := -1
+	var visit func(trail []*Node, depth int, params Params)
+	visit = func(trail []*Node, depth int, params Params) {
+		node := trail[len(trail)-1]
+		if depth > bestDepth {
+			if routes := (lookup{}).appendRoutes(nil, trail, params); len(routes) > 0 {
+				best, bestDepth = routes[0], depth
+			}
+		}
+		if depth == len(path) {
+			return
+		}
+		segment := path[depth]
+		if child := r.staticChild(node, segment); child != nil {
+			visit(extend(trail, child), depth+1, params)
+		}
+		for _, child := range node.sortedChildren() {
+			if child.nodeType != ParamNode || bestDepth == len(path) {
+				continue
+			}
+			if child.constraint != nil && !child.constraint.MatchString(segment) {
+				continue
+			}
+			visit(extend(trail, child), depth+1, append(params[:len(params):len(params)], RouteParam{
+				Key:    child.paramName,
+				Values: r.paramValues(path[depth : depth+1]),
+			}))
+		}
+	}
+	visit([]*Node{r.root.Load()}, 0, nil)
+
+	if bestDepth < 0 {
+		return Route{}, path, false
+	}
+	return best, path[bestDepth:], true
+}
+
+// paramChild returns the first param child of n, by name, whose constraint
+// accepts segment.
+func (n *Node) paramChild(segment string) *Node {
+	var found *Node
+	for _, child := range n.params_children {
+		if child.constraint != nil && !child.constraint.MatchString(segment) {
+			continue
+		}
+		if found == nil || child.paramName < found.paramName {
+			found = child
+		}
+	}
+	return found
+}
+
+// HasPrefix reports whether any route can match a path starting with
+// prefix: either a route is registered at or below the node prefix leads to,
+// through static or param children, or a wildcard covers the rest of it.
+func (r *RadixTree) HasPrefix(prefix []string) bool {
+	return r.hasPrefix(r.root.Load(), prefix)
+}
+
+func (r *RadixTree) hasPrefix(node *Node, prefix []string) bool {
+	if len(prefix) == 0 {
+		return node.nodeSize > 0
+	}
+	if child := r.staticChild(node, prefix[0]); child != nil && r.hasPrefix(child, prefix[1:]) {
+		return true
+	}
+	for _, child := range node.params_children {
+		if child.constraint != nil && !child.constraint.MatchString(prefix[0]) {
+			continue
+		}
+		if r.hasPrefix(child, prefix[1:]) {
+			return true
+		}
+	}
+	return len(node.wildcard_children) > 0
+}
@@ -0,0 +1,113 @@
+package radix_test
+
+import (
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLongestPrefix(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"a"}, "a")
+	tree.Add([]string{"a", "b", "c"}, "a_b_c")
+	tree.Add([]string{"service", ":name"}, "service")
+
+	route, rest, found := tree.LongestPrefix([]string{"a", "b", "x"})
+	assert.True(t, found)
+	assert.Equal(t, "a", route.Handler.(string))
+	assert.Equal(t, []string{"b", "x"}, rest)
+
+	route, rest, found = tree.LongestPrefix([]string{"a", "b", "c"})
+	assert.True(t, found)
+	assert.Equal(t, "a_b_c", route.Handler.(string))
+	assert.Empty(t, rest)
+
+	route, rest, found = tree.LongestPrefix([]string{"a", "b", "c", "d", "e"})
+	assert.True(t, found)
+	assert.Equal(t, "a_b_c", route.Handler.(string))
+	assert.Equal(t, []string{"d", "e"}, rest)
+
+	route, rest, found = tree.LongestPrefix([]string{"service", "billing", "v1", "invoices"})
+	assert.True(t, found)
+	assert.Equal(t, "service", route.Handler.(string))
+	assert.Equal(t, radix.Params{{Key: "name", Values: []string{"billing"}}}, route.Params)
+	assert.Equal(t, []string{"v1", "invoices"}, rest)
+
+	_, rest, found = tree.LongestPrefix([]string{"unknown", "path"})
+	assert.False(t, found)
+	assert.Equal(t, []string{"unknown", "path"}, rest)
+}
+
+func TestLongestPrefixRoot(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{}, "root")
+	tree.Add([]string{"files", "*filepath"}, "files")
+
+	route, rest, found := tree.LongestPrefix([]string{"files", "a"})
+	assert.True(t, found)
+	assert.Equal(t, "root", route.Handler.(string), "Wildcards should not be followed")
+	assert.Equal(t, []string{"files", "a"}, rest)
+}
+
+func TestHasPrefix(t *testing.T) {
+	tree := radix.NewRadixTree()
+	assert.False(t, tree.HasPrefix([]string{}), "Empty tree has no routes")
+
+	tree.Add([]string{"api", "v1", "users"}, "users")
+	tree.Add([]string{"repos", ":owner", "issues"}, "issues")
+	tree.Add([]string{"files", "*filepath"}, "files")
+
+	assert.True(t, tree.HasPrefix([]string{}))
+	assert.True(t, tree.HasPrefix([]string{"api"}))
+	assert.Len(t, tree.Get([]string{"api"}), 0, "No route is registered at the prefix itself")
+	assert.True(t, tree.HasPrefix([]string{"api", "v1"}))
+	assert.True(t, tree.HasPrefix([]string{"api", "v1", "users"}))
+	assert.False(t, tree.HasPrefix([]string{"api", "v2"}))
+	assert.False(t, tree.HasPrefix([]string{"api", "v1", "users", "42"}))
+
+	assert.True(t, tree.HasPrefix([]string{"repos", "golang"}), "Params should match any segment")
+	assert.False(t, tree.HasPrefix([]string{"repos", "golang", "pulls"}))
+
+	assert.True(t, tree.HasPrefix([]string{"files", "a", "b"}), "Wildcards cover any deeper prefix")
+	assert.False(t, tree.HasPrefix([]string{"unknown"}))
+
+	tree.Delete([]string{"api", "v1", "users"})
+	assert.False(t, tree.HasPrefix([]string{"api"}))
+}
+
+func TestMatchPrefix(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"service", ":name"}, "service")
+	tree.Add([]string{"service", "billing"}, "billing")
+	tree.Add([]string{"service", ":name", "v2", ":version"}, "service_v2")
+
+	route, rest, found := tree.MatchPrefix([]string{"service", "users", "v1", "list"})
+	assert.True(t, found)
+	assert.Equal(t, "service", route.Handler.(string))
+	assert.Equal(t, radix.Params{{Key: "name", Values: []string{"users"}}}, route.Params)
+	assert.Equal(t, []string{"v1", "list"}, rest)
+
+	route, rest, found = tree.MatchPrefix([]string{"service", "billing", "v2", "7", "invoices"})
+	assert.True(t, found)
+	assert.Equal(t, "service_v2", route.Handler.(string), "Deeper handler behind a param should win")
+	assert.Equal(t, radix.Params{
+		{Key: "name", Values: []string{"billing"}},
+		{Key: "version", Values: []string{"7"}},
+	}, route.Params)
+	assert.Equal(t, []string{"invoices"}, rest)
+
+	longest, _, _ := tree.LongestPrefix([]string{"service", "billing", "v2", "7", "invoices"})
+	assert.Equal(t, "billing", longest.Handler.(string), "LongestPrefix follows the static branch only")
+
+	route, rest, found = tree.MatchPrefix([]string{"service", "billing"})
+	assert.True(t, found)
+	assert.Equal(t, "billing", route.Handler.(string), "Static should win among equally deep matches")
+	assert.Empty(t, rest)
+
+	_, rest, found = tree.MatchPrefix([]string{"other"})
+	assert.False(t, found)
+	assert.Equal(t, []string{"other"}, rest)
+}
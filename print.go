@@ -0,0 +1,35 @@
+package radix
+
+import "strings"
+
+var nodeMarkers = map[NodeType]string{
+	ParamNode: " (param)",
+	Wildcard:  " (wildcard)",
+}
+
+// String renders the tree as indented text, one node per line in the order
+// of NodeWrapper.Children. The root is shown as "/", params and wildcards
+// are marked as such, and nodes holding a handler end with "[handler]".
+func (r *RadixTree) String() string {
+	var sb strings.Builder
+	writeTextNode(&sb, r.root.Load(), 0)
+	return sb.String()
+}
+
+func writeTextNode(sb *strings.Builder, node *Node, depth int) {
+	sb.WriteString(strings.Repeat("  ", depth))
+	if depth == 0 {
+		sb.WriteString("/")
+	} else {
+		sb.WriteString(node.path)
+	}
+	sb.WriteString(nodeMarkers[node.nodeType])
+	if node.handler != nil || len(node.methods) > 0 {
+		sb.WriteString(" [handler]")
+	}
+	sb.WriteString("\n")
+
+	for _, child := range node.sortedChildren() {
+		writeTextNode(sb, child, depth+1)
+	}
+}
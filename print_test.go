@@ -0,0 +1,30 @@
+package radix_test
+
+import (
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestString(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{}, "root")
+	tree.Add([]string{"users"}, "users")
+	tree.Add([]string{"users", ":id", "posts"}, "user_posts")
+	tree.AddMethod("GET", []string{"files", "*filepath"}, "files")
+
+	expected := `/ [handler]
+  files
+    *filepath (wildcard) [handler]
+  users [handler]
+    :id (param)
+      posts [handler]
+`
+	assert.Equal(t, expected, tree.String())
+}
+
+func TestStringEmptyTree(t *testing.T) {
+	assert.Equal(t, "/\n", radix.NewRadixTree().String())
+}
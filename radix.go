@@ -1,8 +1,14 @@
 package radix
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 type NodeType uint8
@@ -13,17 +19,41 @@ const (
 	Wildcard           // *wildcard
 )
 
+func (t NodeType) String() string {
+	switch t {
+	case Static:
+		return "static"
+	case ParamNode:
+		return "param"
+	case Wildcard:
+		return "wildcard"
+	}
+	return fmt.Sprintf("NodeType(%d)", uint8(t))
+}
+
 type Node struct {
-	parent            *Node
+	// version is the write that created this copy of the node; see own.
+	version           uint64
 	nodeSize          uint32
 	nodeType          NodeType
 	path              string
 	static_children   map[string]*Node
+	static_keys       []string
 	params_children   map[string]*Node
+	params_keys       []string
 	wildcard_children []*Node
 	handler           Handler
+	methods           []methodHandler
 	paramName         string
 	isWildcard        bool
+	matchesEmpty      bool
+	minSegments       int
+	seq               uint64
+	isFallthrough     bool
+	constraint        *regexp.Regexp
+	optionalChild     *Node
+	defaultValues     []string
+	meta              any
 }
 
 type Handler interface{}
@@ -31,23 +61,81 @@ type Handler interface{}
 type RouteParam struct {
 	Key    string
 	Values []string
+	// Raw holds a wildcard's captured segments joined with "/", as they
+	// appeared in the path, when the RawWildcards option is set.
+	Raw string
 }
 
 type Params []RouteParam
 
 type Route struct {
-	Handler Handler
-	Params  Params
+	Handler     Handler
+	Params      Params
+	Fallthrough bool
+	// Method is the HTTP method the route was matched under. It is empty
+	// for routes registered and looked up through the method-less API.
+	Method string
+	// Meta is the metadata registered with the handler by AddWithMeta.
+	Meta any
+
+	// trail lists the nodes from the root down to the one holding the
+	// route.
+	trail []*Node
 }
 
+// Routes lists the matches of a lookup. Get returns them ordered by
+// specificity, the order Sort restores: at the first segment where two
+// routes differ, a static match comes before a param match, param matches
+// are ordered by param key, and wildcard matches come last in registration
+// order. Handlers on the same node list the method-less one first, then
+// the others by method.
 type Routes []Route
 
+// NodeWrapper gives read access to a node of the tree. Wrappers returned by
+// Root and the Add methods are tied to the live tree: every access finds
+// the node again from the current root, so they see later writes, and a
+// node that has since been removed reads as empty. Wrappers returned by
+// lookups, such as GetLeaf, are pinned to the nodes the lookup saw.
 type NodeWrapper struct {
-	node *Node
+	// tree and steps locate a live node.
+	tree  *RadixTree
+	steps []step
+	// trail pins the wrapper to the nodes from the root down to the node.
+	trail []*Node
+}
+
+// step locates a child from its parent: by segment for static children, by
+// name for params, and by segment and position among the siblings sharing
+// it for wildcards.
+type step struct {
+	nodeType  NodeType
+	path      string
+	paramName string
+	nth       int
 }
 
 type RadixTree struct {
-	root *Node
+	root    atomic.Pointer[Node]
+	mu      sync.Mutex // serializes writers
+	opts    Options
+	journal []journalEntry
+	metrics Metrics
+	// notFound is returned by Resolve when no route matches.
+	notFound Handler
+	// dynamic is set, before publishing, by the first write creating a
+	// param or wildcard node, and never cleared. While it is unset, Get
+	// takes the static-only path.
+	dynamic atomic.Bool
+	// normalizer folds static segments; see SetNormalizer.
+	normalizer func(string) string
+	// comparator matches lookup segments to static children; see
+	// SetComparator.
+	comparator func(routeSeg, inputSeg string) bool
+	// seq numbers registrations, so GetLatest can tell the newest route on
+	// a path; writers update it under mu.
+	seq uint64
+	// version numbers the write in progress; see own.
+	version uint64
 }
 
 func (ps Params) Get(name string) ([]string, bool) {
@@ -59,105 +147,927 @@ func (ps Params) Get(name string) ([]string, bool) {
 	return nil, false
 }
 
-func wrap(n *Node) *NodeWrapper {
+// GetAll returns the values of every param named name, in path order. A
+// route may reuse a name at several levels, as in "/a/:x/b/:x", in which
+// case Get only reports the first.
+func (ps Params) GetAll(name string) [][]string {
+	var all [][]string
+	for _, param := range ps {
+		if param.Key == name {
+			all = append(all, param.Values)
+		}
+	}
+	return all
+}
+
+// GetOne returns the value of the named param as a single string. Wildcard
+// params spanning several segments are joined with "/".
+func (ps Params) GetOne(name string) (string, bool) {
+	values, found := ps.Get(name)
+	if !found {
+		return "", false
+	}
+	if len(values) == 1 {
+		return values[0], true
+	}
+	return strings.Join(values, "/"), true
+}
+
+// GetPath returns the segments captured by the named param joined with "/",
+// such as "a/b/c" for a wildcard matching three segments. It is GetOne under
+// a name that reads better when rebuilding filesystem paths.
+func (ps Params) GetPath(name string) (string, bool) {
+	return ps.GetOne(name)
+}
+
+func wrap(trail []*Node) *NodeWrapper {
 	return &NodeWrapper{
-		node: n,
+		trail: trail,
 	}
 }
 
+// live returns a wrapper tied to r for the node at the end of trail.
+func (r *RadixTree) live(trail []*Node) *NodeWrapper {
+	steps := make([]step, len(trail)-1)
+	for i, n := range trail[1:] {
+		steps[i] = stepTo(trail[i], n)
+	}
+	return &NodeWrapper{tree: r, steps: steps}
+}
+
+// stepTo returns the step from parent to its child n.
+func stepTo(parent, n *Node) step {
+	s := step{nodeType: n.nodeType, path: n.path, paramName: n.paramName}
+	if n.nodeType == Wildcard {
+		for _, wc := range parent.wildcard_children[:slices.Index(parent.wildcard_children, n)] {
+			if wc.path == n.path {
+				s.nth++
+			}
+		}
+	}
+	return s
+}
+
+// find returns the child of parent s leads to, or nil.
+func (s step) find(parent *Node) *Node {
+	switch s.nodeType {
+	case Static:
+		return parent.static_children[s.path]
+	case ParamNode:
+		return parent.params_children[s.paramName]
+	}
+	nth := s.nth
+	for _, wc := range parent.wildcard_children {
+		if wc.path == s.path {
+			if nth == 0 {
+				return wc
+			}
+			nth--
+		}
+	}
+	return nil
+}
+
+// extend returns s followed by v, leaving s's backing array alone.
+func extend[T any](s []T, v T) []T {
+	return append(s[:len(s):len(s)], v)
+}
+
+// resolve returns the trail of nodes from the root down to the node. For a
+// live wrapper whose node has been removed, the missing nodes are replaced
+// by empty ones.
+func (nw *NodeWrapper) resolve() []*Node {
+	if nw.tree == nil {
+		return nw.trail
+	}
+	trail := make([]*Node, 1, len(nw.steps)+1)
+	trail[0] = nw.tree.root.Load()
+	for i, s := range nw.steps {
+		child := s.find(trail[len(trail)-1])
+		if child == nil {
+			for _, s := range nw.steps[i:] {
+				trail = append(trail, &Node{nodeType: s.nodeType, path: s.path, paramName: s.paramName})
+			}
+			break
+		}
+		trail = append(trail, child)
+	}
+	return trail
+}
+
+// child returns a wrapper for n, a child of the node at the end of trail,
+// tied to the live tree if nw is.
+func (nw *NodeWrapper) child(trail []*Node, n *Node) *NodeWrapper {
+	if nw.tree == nil {
+		return wrap(extend(trail, n))
+	}
+	return &NodeWrapper{tree: nw.tree, steps: extend(nw.steps, stepTo(trail[len(trail)-1], n))}
+}
+
+func (nw *NodeWrapper) node() *Node {
+	trail := nw.resolve()
+	return trail[len(trail)-1]
+}
+
 func (nw *NodeWrapper) PathName() string {
-	return nw.node.path
+	return nw.node().path
 }
 
 func (nw *NodeWrapper) Parent() (*NodeWrapper, bool) {
-	return wrap(nw.node.parent), nw.node.parent != nil
+	if nw.tree != nil {
+		if len(nw.steps) == 0 {
+			return nil, false
+		}
+		return &NodeWrapper{tree: nw.tree, steps: nw.steps[:len(nw.steps)-1]}, true
+	}
+	if len(nw.trail) == 1 {
+		return nil, false
+	}
+	return wrap(nw.trail[:len(nw.trail)-1]), true
 }
 
 func (nw *NodeWrapper) Size() uint32 {
-	return nw.node.nodeSize
+	return nw.node().nodeSize
 }
 
 func (nw *NodeWrapper) Equal(w *NodeWrapper) bool {
-	return nw.node == w.node
+	return nw.node() == w.node()
 }
 
 func (nw *NodeWrapper) Path() []string {
-	segments := []string{}
-	current := nw.node
-	for current != nil {
-		segments = append([]string{current.path}, segments...)
-		current = current.parent
+	trail := nw.resolve()
+	segments := make([]string, 0, len(trail)-1)
+	for _, n := range trail[1:] {
+		segments = append(segments, n.path)
 	}
-	return segments[1:]
+	return segments
 }
 
-func NewRadixTree() *RadixTree {
-	return &RadixTree{
-		root: &Node{
-			parent: nil,
-		},
+// Sequence returns the registration number of the last route added to the
+// node, or 0 if none was. Every route registered on a tree gets a number
+// larger than any before it, so Sequence orders nodes by when they were
+// last given a route.
+func (nw *NodeWrapper) Sequence() uint64 {
+	return nw.node().seq
+}
+
+// Pattern returns the route pattern leading to the node, its segments as
+// registered joined with "/" behind a leading slash, such as
+// "/api/v1/users/:id". The root's pattern is "/". Static segments appear as
+// stored, without the escape that registered them.
+func (nw *NodeWrapper) Pattern() string {
+	return pattern(nw.resolve())
+}
+
+// FloorChild returns the static child whose segment is the lexically largest
+// one less than or equal to key.
+func (nw *NodeWrapper) FloorChild(key string) (*NodeWrapper, bool) {
+	trail := nw.resolve()
+	node := trail[len(trail)-1]
+	i, found := slices.BinarySearch(node.static_keys, key)
+	if !found {
+		i--
+	}
+	if i < 0 {
+		return nil, false
+	}
+	return nw.child(trail, node.static_children[node.static_keys[i]]), true
+}
+
+// Children returns the node's children: static ones by segment, params by
+// name, then wildcards in registration order.
+func (nw *NodeWrapper) Children() []*NodeWrapper {
+	trail := nw.resolve()
+	children := trail[len(trail)-1].sortedChildren()
+	wrappers := make([]*NodeWrapper, len(children))
+	for i, child := range children {
+		wrappers[i] = nw.child(trail, child)
 	}
+	return wrappers
+}
+
+// NodeType returns whether the node is static, a param or a wildcard.
+func (nw *NodeWrapper) NodeType() NodeType {
+	return nw.node().nodeType
+}
+
+// ParamName returns the name declared by a param or wildcard node, or an
+// empty string for a static node.
+func (nw *NodeWrapper) ParamName() string {
+	return nw.node().paramName
+}
+
+// Handler returns the method-less handler registered on the node.
+func (nw *NodeWrapper) Handler() (Handler, bool) {
+	node := nw.node()
+	return node.handler, node.handler != nil
+}
+
+func NewRadixTree() *RadixTree {
+	return NewRadixTreeWithOptions(Options{})
 }
 
 func (r *RadixTree) Root() *NodeWrapper {
-	return wrap(r.root)
+	return &NodeWrapper{tree: r, steps: []step{}}
 }
 
 func (r *RadixTree) Size() uint32 {
-	return r.root.nodeSize
+	return r.root.Load().nodeSize
 }
 
+// TopLevelSegments returns the first segments of the registered routes, as
+// Walk reports them: static segments in sorted order, then root params by
+// name, then root wildcards. An empty tree returns an empty slice.
+func (r *RadixTree) TopLevelSegments() []string {
+	children := r.root.Load().sortedChildren()
+	segments := make([]string, len(children))
+	for i, child := range children {
+		segments[i] = r.label(child)
+	}
+	return segments
+}
+
+// Add registers handler at path. Like every write, it is applied to a copy
+// of the tree that is published atomically once complete, so lookups never
+// take a lock and never observe a half-applied change. The returned
+// wrapper refers to the published version of the tree.
 func (r *RadixTree) Add(path []string, handler Handler) (*NodeWrapper, error) {
-	return r.addRoute(r.root, path, handler)
+	var trail []*Node
+	err := r.update(func(root *Node) (err error) {
+		if trail, err = r.addRoute(root, path, assignHandler(handler), nil); err != nil {
+			return err
+		}
+		r.record(journalEntry{Op: journalAdd, Path: path})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.live(trail), nil
+}
+
+// CanAdd reports whether Add would register a handler at path, returning
+// the error Add would return, without changing the tree. Like Add, it works
+// on a copy of the tree.
+func (r *RadixTree) CanAdd(path []string) error {
+	scratch := &RadixTree{opts: r.opts, normalizer: r.normalizer, version: versions.Add(1)}
+	root := scratch.own(r.root.Load())
+	if _, err := scratch.addRoute(root, path, assignHandler(struct{}{}), nil); err != nil {
+		return err
+	}
+	return r.checkLimit(root)
 }
 
+// SetRoot registers handler for the root path "/", the route Get returns
+// for an empty path. It is equivalent to Add with an empty path and returns
+// ErrHandlerExists if the root already has a handler.
+func (r *RadixTree) SetRoot(handler Handler) error {
+	_, err := r.Add([]string{}, handler)
+	return err
+}
+
+// AddWithConstraints registers handler like Add, attaching a regular
+// expression to each named parameter in constraints. A parameter segment
+// only matches lookup segments accepted by its constraint. Constraints are
+// stored on the param node and shared by every route passing through it.
+func (r *RadixTree) AddWithConstraints(path []string, handler Handler, constraints map[string]*regexp.Regexp) (*NodeWrapper, error) {
+	for name := range constraints {
+		if !slices.ContainsFunc(path, func(segment string) bool {
+			nodeType, paramName := r.classify(segment)
+			return nodeType == ParamNode && paramName == name
+		}) {
+			return nil, fmt.Errorf("constraint for unknown parameter %q", name)
+		}
+	}
+	var trail []*Node
+	err := r.update(func(root *Node) (err error) {
+		if trail, err = r.addRoute(root, path, assignHandler(handler), constraints); err != nil {
+			return err
+		}
+		r.record(journalEntry{Op: journalAdd, Path: path, Constraints: constraintSources(constraints)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.live(trail), nil
+}
+
+// Get returns every route matching path, ordered as described on Routes, or
+// nil when none does; the lookups built on it also return nil on a miss.
+// Wildcard siblings keep the order they were registered in: a wildcard that
+// is deleted and added again moves behind the others.
 func (r *RadixTree) Get(path []string) Routes {
-	return r.getValue(r.root, path, nil)
+	// The root must be loaded before dynamic is checked: a root holding
+	// dynamic nodes is only published after the flag is set.
+	root := r.root.Load()
+	var routes Routes
+	if r.dynamic.Load() {
+		var buf trailBuffer
+		routes = r.getValue(buf.start(root), path, nil, lookup{})
+	} else {
+		routes = r.getStatic(root, path)
+	}
+	if r.metrics != nil {
+		r.observe(path, routes)
+	}
+	return routes
+}
+
+// GetBatch looks up each of paths like Get and returns their routes in the
+// same order, so the i-th result belongs to paths[i]. All paths are matched
+// against the same snapshot of the tree, even while other goroutines modify
+// it.
+func (r *RadixTree) GetBatch(paths [][]string) []Routes {
+	snapshot := r.Snapshot()
+	results := make([]Routes, len(paths))
+	for i, path := range paths {
+		results[i] = snapshot.Get(path)
+	}
+	return results
+}
+
+// getStatic looks up path in a tree without param or wildcard nodes.
+func (r *RadixTree) getStatic(root *Node, path []string) Routes {
+	var buf trailBuffer
+	trail := buf.start(root)
+	for _, segment := range path {
+		node := r.staticChild(trail[len(trail)-1], segment)
+		if node == nil {
+			return nil
+		}
+		trail = append(trail, node)
+	}
+	return lookup{}.appendRoutes(nil, trail, nil)
+}
+
+// trailBuffer holds the trail of a typical lookup on the stack. Lookups
+// reuse it as scratch space and copy it only into the routes they find.
+type trailBuffer [16]*Node
+
+// start returns a trail holding only root.
+func (b *trailBuffer) start(root *Node) []*Node {
+	b[0] = root
+	return b[:1]
+}
+
+// GetInto looks up path like Get, appending captured params into buf
+// instead of allocating them, so callers can pool buffers (for example with
+// sync.Pool) and pass buf[:0] on each request. The Params of the returned
+// routes share buf's backing array and are only valid until buf is reused.
+func (r *RadixTree) GetInto(path []string, buf Params) Routes {
+	var trail trailBuffer
+	return r.getValue(trail.start(r.root.Load()), path, buf[:0], lookup{})
+}
+
+// GetN returns at most n of the routes Get would return, in the same
+// priority order. Lower-priority branches are not explored once n routes
+// have been found. With n <= 0 it behaves like Get.
+func (r *RadixTree) GetN(path []string, n int) Routes {
+	var buf trailBuffer
+	return r.getValue(buf.start(r.root.Load()), path, nil, lookup{limit: n})
+}
+
+// GetContext looks up path like Get, but checks ctx at every node where the
+// lookup branches and returns ctx.Err() once ctx is done, so a deadline can
+// cut short a match fanning out over a very bushy tree.
+func (r *RadixTree) GetContext(ctx context.Context, path []string) (Routes, error) {
+	var buf trailBuffer
+	routes := r.getValue(buf.start(r.root.Load()), path, nil, lookup{ctx: ctx})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// GetTraced looks up path like Get and calls trace for every node the
+// lookup visits, after the nodes below it, with whether a route was found
+// at or below it. Nodes pruned without being visited, such as params whose
+// constraint rejects the segment, are not reported. The wrappers trace
+// receives are read-only views of the tree as the lookup saw it.
+func (r *RadixTree) GetTraced(path []string, trace func(node *NodeWrapper, matched bool)) Routes {
+	var buf trailBuffer
+	return r.getValue(buf.start(r.root.Load()), path, nil, lookup{trace: trace})
+}
+
+// AddWithMeta registers handler like Add and attaches meta to it, such as a
+// route name or an auth scope. Routes matched through the handler carry
+// meta in Route.Meta; it is removed along with the handler. Handlers added
+// with AddMethod have no metadata.
+func (r *RadixTree) AddWithMeta(path []string, handler Handler, meta any) error {
+	assign := func(node *Node) error {
+		if err := assignHandler(handler)(node); err != nil {
+			return err
+		}
+		node.meta = meta
+		return nil
+	}
+	return r.update(func(root *Node) error {
+		if _, err := r.addRoute(root, path, assign, nil); err != nil {
+			return err
+		}
+		r.record(journalEntry{Op: journalAdd, Path: path})
+		return nil
+	})
+}
+
+// AddFallthrough registers handler like Add, but marks the route as
+// fallthrough: GetOne may skip it in favour of the next lower-priority match.
+func (r *RadixTree) AddFallthrough(path []string, handler Handler) (*NodeWrapper, error) {
+	var trail []*Node
+	err := r.update(func(root *Node) (err error) {
+		if trail, err = r.addRoute(root, path, assignHandler(handler), nil); err != nil {
+			return err
+		}
+		trail[len(trail)-1].isFallthrough = true
+		r.record(journalEntry{Op: journalAdd, Path: path, Fallthrough: true})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.live(trail), nil
+}
+
+// GetLatest returns the route matching path that was registered last,
+// falling back to priority order among handlers on the same node. Later
+// registrations thus override earlier ones that match the same paths. A
+// route that is deleted and added again counts as new.
+func (r *RadixTree) GetLatest(path []string) (Route, bool) {
+	routes := r.Get(path)
+	if len(routes) == 0 {
+		return Route{}, false
+	}
+	latest := routes[0]
+	for _, route := range routes[1:] {
+		if route.leaf().seq > latest.leaf().seq {
+			latest = route
+		}
+	}
+	return latest, true
+}
+
+// GetWithAncestor returns the highest-priority route matching path together
+// with the route of the deepest node above it that holds a handler, such as
+// a group handler at "/admin" for a match at "/admin/users". The ancestor's
+// Params are those captured on the way down to it; when no ancestor holds a
+// handler, ancestor is the zero Route.
+func (r *RadixTree) GetWithAncestor(path []string) (exact Route, ancestor Route, found bool) {
+	routes := r.GetN(path, 1)
+	if len(routes) == 0 {
+		return Route{}, Route{}, false
+	}
+	exact = routes[0]
+	captured := len(exact.Params)
+	for i := len(exact.trail) - 1; i > 0; i-- {
+		if exact.trail[i].nodeType != Static {
+			captured--
+		}
+		if candidates := (lookup{}).appendRoutes(nil, exact.trail[:i], exact.Params[:captured:captured]); len(candidates) > 0 {
+			return exact, candidates[0], true
+		}
+	}
+	return exact, Route{}, true
+}
+
+// GetOne returns the highest-priority route matching path. Fallthrough routes
+// are offered to accept first; when accept rejects one, matching continues
+// with the next route in priority order.
+func (r *RadixTree) GetOne(path []string, accept func(Route) bool) (Route, bool) {
+	for _, route := range r.Get(path) {
+		if route.Fallthrough && accept != nil && !accept(route) {
+			continue
+		}
+		return route, true
+	}
+	return Route{}, false
+}
+
+func (r *RadixTree) findChild(n *Node, segment string) *Node {
+	switch nodeType, name := r.classify(segment); nodeType {
+	case Wildcard:
+		for _, wc := range n.wildcard_children {
+			if wc.path == segment {
+				return wc
+			}
+		}
+		return nil
+	case ParamNode:
+		return n.params_children[name]
+	default:
+		return n.static_children[name]
+	}
+}
+
+// findNode follows the registered route segments in path, without matching
+// params or wildcards against them, and returns the node they lead to.
+func (r *RadixTree) findNode(node *Node, path []string) *Node {
+	for _, segment := range path {
+		if node = r.findChild(node, segment); node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// findTrail follows path from root like findNode and returns the trail to
+// the node it leads to, or nil if there is no such node.
+func (r *RadixTree) findTrail(root *Node, path []string) []*Node {
+	trail := make([]*Node, 1, len(path)+1)
+	trail[0] = root
+	for _, segment := range path {
+		child := r.findChild(trail[len(trail)-1], segment)
+		if child == nil {
+			return nil
+		}
+		trail = append(trail, child)
+	}
+	return trail
+}
+
+// ownTrail follows path from root like findNode and returns the trail to
+// the node it leads to, with every node on it owned by the write in
+// progress, or nil if there is no such node.
+func (r *RadixTree) ownTrail(root *Node, path []string) []*Node {
+	trail := make([]*Node, 1, len(path)+1)
+	trail[0] = root
+	for _, segment := range path {
+		node := trail[len(trail)-1]
+		child := r.findChild(node, segment)
+		if child == nil {
+			return nil
+		}
+		trail = append(trail, r.ownChild(node, child))
+	}
+	return trail
+}
+
+// versions numbers the writes to every tree. A node created or copied by a
+// write carries its number, which tells the nodes the write may modify in
+// place from those it shares with published versions of the tree.
+var versions atomic.Uint64
+
+// own returns n if the write in progress owns it, or else a copy of n that
+// it owns. The copy shares the children of n, in collections of its own so
+// that they can be relinked.
+func (r *RadixTree) own(n *Node) *Node {
+	if n.version == r.version {
+		return n
+	}
+	c := *n
+	c.version = r.version
+	c.static_children = maps.Clone(n.static_children)
+	c.static_keys = slices.Clone(n.static_keys)
+	c.params_children = maps.Clone(n.params_children)
+	c.params_keys = slices.Clone(n.params_keys)
+	c.wildcard_children = slices.Clone(n.wildcard_children)
+	c.methods = slices.Clone(n.methods)
+	return &c
+}
+
+// ownChild returns child owned by the write in progress, linked below
+// parent, which must be owned already, in place of the original.
+func (r *RadixTree) ownChild(parent, child *Node) *Node {
+	if child.version == r.version {
+		return child
+	}
+	c := r.own(child)
+	switch child.nodeType {
+	case Static:
+		parent.static_children[child.path] = c
+	case ParamNode:
+		parent.params_children[child.paramName] = c
+		if parent.optionalChild == child {
+			parent.optionalChild = c
+		}
+	case Wildcard:
+		parent.wildcard_children[slices.Index(parent.wildcard_children, child)] = c
+	}
+	return c
+}
+
+// update applies fn to a copy of the tree and publishes the copy only if fn
+// succeeds, so concurrent readers never observe a partially applied change.
+// Only the root is copied up front; fn copies the nodes below it that it
+// changes, with ownChild or ownTrail, and shares the rest with the
+// published version.
+func (r *RadixTree) update(fn func(root *Node) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.version = versions.Add(1)
+	root := r.own(r.root.Load())
+	recorded := len(r.journal)
+	err := fn(root)
+	if err == nil {
+		err = r.checkLimit(root)
+	}
+	if err != nil {
+		if r.journal != nil {
+			r.journal = r.journal[:recorded]
+		}
+		return err
+	}
+	r.root.Store(root)
+	return nil
+}
+
+// checkLimit returns ErrTooManyRoutes if the tree at root holds more routes
+// than MaxRoutes allows.
+func (r *RadixTree) checkLimit(root *Node) error {
+	if r.opts.MaxRoutes > 0 && root.nodeSize > r.opts.MaxRoutes {
+		return fmt.Errorf("%w: limit is %d", ErrTooManyRoutes, r.opts.MaxRoutes)
+	}
+	return nil
+}
+
+// GetDeepest returns the matching route whose handler node lies deepest in
+// the tree. Ties are broken by the usual static, param, wildcard priority.
+func (r *RadixTree) GetDeepest(path []string) (Route, bool) {
+	routes := r.Get(path)
+	if len(routes) == 0 {
+		return Route{}, false
+	}
+	best, bestDepth := 0, len(routes[0].trail)
+	for i := 1; i < len(routes); i++ {
+		if depth := len(routes[i].trail); depth > bestDepth {
+			best, bestDepth = i, depth
+		}
+	}
+	return routes[best], true
 }
 
+// GetLeaf returns the node holding the highest-priority route matching
+// path, together with the params it captured. A wildcard match returns the
+// wildcard node. The wrapper keeps showing the node as the lookup found it,
+// even after later writes.
+func (r *RadixTree) GetLeaf(path []string) (*NodeWrapper, Params, bool) {
+	routes := r.GetN(path, 1)
+	if len(routes) == 0 {
+		return nil, nil, false
+	}
+	return wrap(routes[0].trail), routes[0].Params, true
+}
+
+// GetByPattern returns the params captured by every matching route, keyed by
+// the route's registered pattern such as "/api/:version".
+func (r *RadixTree) GetByPattern(path []string) map[string]Params {
+	routes := r.Get(path)
+	patterns := make(map[string]Params, len(routes))
+	for _, route := range routes {
+		patterns[pattern(route.trail)] = route.Params
+	}
+	return patterns
+}
+
+// Delete removes the handler registered at path. The deletion is applied to
+// a copy of the root-to-target path which is then published atomically, so
+// concurrent Get calls keep reading the previous version and never observe
+// a partially pruned tree.
 func (r *RadixTree) Delete(path []string) error {
-	return r.deleteRoute(r.root, path)
+	return r.update(func(root *Node) error {
+		if err := r.deleteRoute(root, "", path); err != nil {
+			return err
+		}
+		r.record(journalEntry{Op: journalDelete, Path: path})
+		return nil
+	})
 }
 
-func (r *RadixTree) addRoute(node *Node, segments []string, handler Handler) (*NodeWrapper, error) {
+// DeleteIf removes the handler registered at path like Delete, but only if
+// pred accepts it; otherwise it returns ErrHandlerMismatch and leaves the
+// tree unchanged. pred is called with the writer lock held, so the handler
+// it approves is the one removed. A nil pred is an error.
+func (r *RadixTree) DeleteIf(path []string, pred func(Handler) bool) error {
+	if pred == nil {
+		return fmt.Errorf("predicate cannot be nil")
+	}
+	return r.update(func(root *Node) error {
+		if node := r.findNode(root, path); node != nil && node.handler != nil && !pred(node.handler) {
+			return fmt.Errorf("%w: %q", ErrHandlerMismatch, "/"+strings.Join(path, "/"))
+		}
+		if err := r.deleteRoute(root, "", path); err != nil {
+			return err
+		}
+		r.record(journalEntry{Op: journalDelete, Path: path})
+		return nil
+	})
+}
+
+// DeleteSubtree removes every route registered at or below prefix and
+// returns how many were removed. Like Delete, it is applied to a copy of the
+// tree. An empty prefix removes every route.
+func (r *RadixTree) DeleteSubtree(prefix []string) (int, error) {
+	removed := 0
+	err := r.update(func(root *Node) error {
+		trail := r.ownTrail(root, prefix)
+		if trail == nil {
+			return fmt.Errorf("%w: %q", ErrPathNotFound, "/"+strings.Join(prefix, "/"))
+		}
+		node := trail[len(trail)-1]
+		removed = int(node.nodeSize)
+		if len(trail) == 1 {
+			*node = Node{version: r.version}
+		} else {
+			trail[len(trail)-2].removeChild(node)
+			for i := len(trail) - 2; i >= 0; i-- {
+				trail[i].nodeSize -= node.nodeSize
+				if trail[i].nodeSize == 0 && i > 0 {
+					trail[i-1].removeChild(trail[i])
+				}
+			}
+		}
+		r.record(journalEntry{Op: journalPrune, Path: prefix})
+		return nil
+	})
+	return removed, err
+}
+
+// Clear removes every route from the tree while keeping the same *RadixTree.
+func (r *RadixTree) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.root.Store(&Node{})
+	r.record(journalEntry{Op: journalClear})
+}
+
+// Clone returns a copy of the tree. Handlers are shared by reference;
+// adding or deleting routes on either tree does not affect the other. The
+// two trees share their nodes until either one writes, which copies the
+// nodes it changes, so cloning copies nothing up front.
+func (r *RadixTree) Clone() *RadixTree {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clone := &RadixTree{
+		opts:       r.opts,
+		journal:    slices.Clone(r.journal),
+		metrics:    r.metrics,
+		notFound:   r.notFound,
+		normalizer: r.normalizer,
+		comparator: r.comparator,
+		seq:        r.seq,
+	}
+	clone.root.Store(r.root.Load())
+	clone.dynamic.Store(r.dynamic.Load())
+	return clone
+}
+
+// pattern returns the pattern of the node at the end of trail, such as
+// "/users/:id".
+func pattern(trail []*Node) string {
+	segments := make([]string, len(trail)-1)
+	for i, n := range trail[1:] {
+		segments[i] = n.path
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// ambiguityError reports that adding segment below the node at the end of
+// trail would make it match the same paths as the existing sibling.
+func ambiguityError(trail []*Node, segment string, sibling *Node) error {
+	return fmt.Errorf("%w: %q conflicts with %q", ErrAmbiguousRoute, childPattern(trail, segment), childPattern(trail, sibling.path))
+}
+
+// kindConflictError reports that a child of the given kind cannot be added
+// to the node at the end of trail next to sibling, a child of another kind,
+// under StrictChildren.
+func kindConflictError(trail []*Node, segment string, kind NodeType, sibling *Node) error {
+	return fmt.Errorf("%w: %s %q conflicts with %s %q", ErrAmbiguousRoute, kind, childPattern(trail, segment), sibling.nodeType, childPattern(trail, sibling.path))
+}
+
+// childPattern returns the pattern of segment registered below the node at
+// the end of trail.
+func childPattern(trail []*Node, segment string) string {
+	if len(trail) == 1 {
+		return "/" + segment
+	}
+	return pattern(trail) + "/" + segment
+}
+
+// cloneNode returns a deep copy of node owned by the given write.
+func cloneNode(node *Node, version uint64) *Node {
+	clone := *node
+	clone.version = version
+	if node.static_children != nil {
+		clone.static_children = make(map[string]*Node, len(node.static_children))
+		for key, child := range node.static_children {
+			clone.static_children[key] = cloneNode(child, version)
+		}
+		clone.static_keys = slices.Clone(node.static_keys)
+	}
+	if node.params_children != nil {
+		clone.params_children = make(map[string]*Node, len(node.params_children))
+		for key, child := range node.params_children {
+			clone.params_children[key] = cloneNode(child, version)
+		}
+		clone.params_keys = slices.Clone(node.params_keys)
+	}
+	if node.optionalChild != nil {
+		clone.optionalChild = clone.params_children[node.optionalChild.paramName]
+	}
+	clone.methods = slices.Clone(node.methods)
+	if node.wildcard_children != nil {
+		clone.wildcard_children = make([]*Node, len(node.wildcard_children))
+		for i, child := range node.wildcard_children {
+			clone.wildcard_children[i] = cloneNode(child, version)
+		}
+	}
+	return &clone
+}
+
+// assignHandler returns the leaf assignment used by Add: it sets the
+// method-less handler of a node that does not have one yet.
+func assignHandler(handler Handler) func(*Node) error {
+	return func(node *Node) error {
+		if handler == nil {
+			return ErrNilHandler
+		}
+		if node.handler != nil || node.optionalChild != nil {
+			return fmt.Errorf("%w: %q", ErrHandlerExists, node.path)
+		}
+		node.handler = handler
+		return nil
+	}
+}
+
+// stamp records that a route was just registered on node, for GetLatest.
+func (r *RadixTree) stamp(node *Node) {
+	r.seq++
+	node.seq = r.seq
+}
+
+// addRoute creates the nodes for segments below root and calls assign on
+// the final one to attach the route's handler. It returns the trail from
+// root down to that node, every node on it owned by the write in progress.
+func (r *RadixTree) addRoute(root *Node, segments []string, assign func(*Node) error, constraints map[string]*regexp.Regexp) ([]*Node, error) {
+	trail := make([]*Node, 1, len(segments)+1)
+	trail[0] = root
+	return r.insert(trail, segments, assign, constraints)
+}
+
+// insert adds the route for segments below the node at the end of trail.
+func (r *RadixTree) insert(trail []*Node, segments []string, assign func(*Node) error, constraints map[string]*regexp.Regexp) ([]*Node, error) {
+	node := trail[len(trail)-1]
 	if len(segments) == 0 {
-		if node.handler != nil {
-			return nil, fmt.Errorf("handler already exists for this path")
+		if err := assign(node); err != nil {
+			return nil, err
 		}
+		r.stamp(node)
 		node.nodeSize++
-		node.handler = handler
-		return wrap(node), nil
+		return trail, nil
 	}
 
 	segment := segments[0]
 	remaining := segments[1:]
 	err := error(nil)
-	var nw *NodeWrapper
 
-	if strings.HasPrefix(segment, "*") {
-		nw, err = r.addWildcardChild(node, segment, remaining, handler)
-	} else if strings.HasPrefix(segment, ":") {
-		nw, err = r.addParamChild(node, segment, remaining, handler)
-	} else {
-		nw, err = r.addStaticChild(node, segment, remaining, handler)
+	nodeType, name := r.classify(segment)
+	if r.opts.StrictNames && nodeType != Static && name == "" {
+		return nil, fmt.Errorf("%w: %q", ErrEmptyName, segment)
+	}
+	if r.opts.UniqueParams && nodeType != Static && name != "" && capturesParam(trail, name) {
+		path := append(r.segments(trail[1:]), segments...)
+		return nil, fmt.Errorf("%w: %q in %v", ErrDuplicateParam, name, path)
+	}
+	switch nodeType {
+	case Wildcard:
+		trail, err = r.addWildcardChild(trail, segment, name, remaining, assign, constraints)
+	case ParamNode:
+		trail, err = r.addParamChild(trail, segment, name, remaining, assign, constraints)
+	default:
+		trail, err = r.addStaticChild(trail, name, remaining, assign, constraints)
 	}
 	if err == nil {
 		node.nodeSize++
 	}
-	return nw, err
+	return trail, err
+}
+
+// capturesParam reports whether a node of trail is a param or wildcard
+// captured under name.
+func capturesParam(trail []*Node, name string) bool {
+	for _, n := range trail {
+		if n.nodeType != Static && n.paramName == name {
+			return true
+		}
+	}
+	return false
 }
 
-func (r *RadixTree) addStaticChild(node *Node, segment string, remaining []string, handler Handler) (*NodeWrapper, error) {
+func (r *RadixTree) addStaticChild(trail []*Node, segment string, remaining []string, assign func(*Node) error, constraints map[string]*regexp.Regexp) ([]*Node, error) {
+	node := trail[len(trail)-1]
 	if child, exists := node.static_children[segment]; exists {
-		return r.addRoute(child, remaining, handler)
+		return r.insert(append(trail, r.ownChild(node, child)), remaining, assign, constraints)
 	}
 
+	if r.opts.StrictChildren && len(node.params_children) > 0 {
+		return nil, kindConflictError(trail, segment, Static, node.sortedChildren()[len(node.static_keys)])
+	}
 	child := &Node{
+		version:  r.version,
 		nodeType: Static,
 		path:     segment,
-		parent:   node,
 	}
-	nw, err := r.addRoute(child, remaining, handler)
+	leaf, err := r.insert(append(trail, child), remaining, assign, constraints)
 	if err != nil {
 		return nil, err
 	}
@@ -166,22 +1076,36 @@ func (r *RadixTree) addStaticChild(node *Node, segment string, remaining []strin
 		node.static_children = make(map[string]*Node)
 	}
 	node.static_children[child.path] = child
-	return nw, nil
+	i, _ := slices.BinarySearch(node.static_keys, child.path)
+	node.static_keys = slices.Insert(node.static_keys, i, child.path)
+	return leaf, nil
 }
 
-func (r *RadixTree) addParamChild(node *Node, segment string, remaining []string, handler Handler) (*NodeWrapper, error) {
-	segmentParam := segment[1:]
+func (r *RadixTree) addParamChild(trail []*Node, segment string, segmentParam string, remaining []string, assign func(*Node) error, constraints map[string]*regexp.Regexp) ([]*Node, error) {
+	node := trail[len(trail)-1]
+	constraint := constraints[segmentParam]
 
 	if child, exists := node.params_children[segmentParam]; exists {
-		return r.addRoute(child, remaining, handler)
+		if constraint != nil && (child.constraint == nil || child.constraint.String() != constraint.String()) {
+			return nil, fmt.Errorf("%w: %q", ErrConstraintConflict, segment)
+		}
+		return r.insert(append(trail, r.ownChild(node, child)), remaining, assign, constraints)
+	}
+	if r.opts.StrictChildren && len(node.static_children) > 0 {
+		return nil, kindConflictError(trail, segment, ParamNode, node.static_children[node.static_keys[0]])
+	}
+	if r.opts.StrictWildcards && len(node.wildcard_children) > 0 {
+		return nil, ambiguityError(trail, segment, node.wildcard_children[0])
 	}
+	r.dynamic.Store(true)
 	child := &Node{
-		nodeType:  ParamNode,
-		path:      segment,
-		paramName: segmentParam,
-		parent:    node,
+		version:    r.version,
+		nodeType:   ParamNode,
+		path:       segment,
+		paramName:  segmentParam,
+		constraint: constraint,
 	}
-	nw, err := r.addRoute(child, remaining, handler)
+	leaf, err := r.insert(append(trail, child), remaining, assign, constraints)
 	if err != nil {
 		return nil, err
 	}
@@ -190,158 +1114,375 @@ func (r *RadixTree) addParamChild(node *Node, segment string, remaining []string
 		node.params_children = make(map[string]*Node)
 	}
 	node.params_children[child.paramName] = child
-	return nw, nil
+	i, _ := slices.BinarySearch(node.params_keys, child.paramName)
+	node.params_keys = slices.Insert(node.params_keys, i, child.paramName)
+	return leaf, nil
 }
 
-func (r *RadixTree) addWildcardChild(node *Node, segment string, remaining []string, handler Handler) (*NodeWrapper, error) {
+func (r *RadixTree) addWildcardChild(trail []*Node, segment string, name string, remaining []string, assign func(*Node) error, constraints map[string]*regexp.Regexp) ([]*Node, error) {
+	node := trail[len(trail)-1]
 	if len(remaining) > 0 {
-		return nil, fmt.Errorf("wildcard must be the last segment")
+		return nil, fmt.Errorf("%w: %q", ErrWildcardNotLast, segment)
+	}
+	// The same wildcard may be registered more than once: a handler that
+	// conflicts with every existing copy gets a sibling of its own.
+	for _, child := range node.wildcard_children {
+		if child.path != segment {
+			continue
+		}
+		child = r.ownChild(node, child)
+		if assign(child) == nil {
+			r.stamp(child)
+			child.nodeSize++
+			return append(trail, child), nil
+		}
+	}
+	if r.opts.StrictWildcards {
+		if len(node.wildcard_children) > 0 {
+			return nil, ambiguityError(trail, segment, node.wildcard_children[0])
+		}
+		for _, param := range node.params_children {
+			return nil, ambiguityError(trail, segment, param)
+		}
 	}
+	r.dynamic.Store(true)
 	child := &Node{
-		nodeType:   Wildcard,
-		path:       segment,
-		paramName:  segment[1:],
-		isWildcard: true,
-		handler:    handler,
-		parent:     node,
-		nodeSize:   1,
+		version:      r.version,
+		nodeType:     Wildcard,
+		path:         segment,
+		paramName:    name,
+		isWildcard:   true,
+		matchesEmpty: r.matchesEmpty(segment),
+		nodeSize:     1,
 	}
+	if err := assign(child); err != nil {
+		return nil, err
+	}
+	r.stamp(child)
 	node.wildcard_children = append(node.wildcard_children, child)
-	return wrap(child), nil
+	return append(trail, child), nil
 }
 
-func (r *RadixTree) getValue(node *Node, segments []string, params Params) Routes {
-	if len(segments) == 0 {
+// lookup holds the per-call settings of getValue.
+type lookup struct {
+	// method restricts matches to handlers registered for it, falling back
+	// to method-less handlers. When empty, every handler matches.
+	method string
+	// limit caps the number of collected routes; 0 means unlimited.
+	limit int
+	// ctx, when set, abandons the lookup once it is done.
+	ctx context.Context
+	// trace, when set, is called for every node the lookup visits.
+	trace func(node *NodeWrapper, matched bool)
+}
+
+// appendLeaf appends the routes of the node at the end of trail, a child
+// matched without descending into it, reporting it to lk.trace when
+// tracing.
+func (lk lookup) appendLeaf(routes Routes, trail []*Node, params Params) Routes {
+	n := len(routes)
+	routes = lk.appendRoutes(routes, trail, params)
+	if lk.trace != nil {
+		lk.trace(wrap(slices.Clone(trail)), len(routes) > n)
+	}
+	return routes
+}
+
+// appendRoutes appends a route for every handler accepted by lk of the node
+// at the end of trail. The routes get a copy of trail, which lookups reuse.
+func (lk lookup) appendRoutes(routes Routes, trail []*Node, params Params) Routes {
+	node := trail[len(trail)-1]
+	if node.handler == nil && (len(node.methods) == 0 || lk.method != "" && node.methodHandler(lk.method) == nil) {
+		return routes
+	}
+	var copied []*Node
+	if routes == nil && len(trail) <= len(routeBlock{}.trail) {
+		block := &routeBlock{}
+		routes = block.routes[:0]
+		copied = block.trail[:len(trail):len(trail)]
+		copy(copied, trail)
+	} else {
+		copied = slices.Clone(trail)
+	}
+	if lk.method != "" {
+		if handler := node.methodHandler(lk.method); handler != nil {
+			return append(routes, Route{Handler: handler, Params: params, Fallthrough: node.isFallthrough, Method: lk.method, trail: copied})
+		}
 		if node.handler != nil {
-			return Routes{{Handler: node.handler, Params: params}}
+			routes = append(routes, Route{Handler: node.handler, Params: params, Fallthrough: node.isFallthrough, Meta: node.meta, trail: copied})
 		}
-		return Routes{}
+		return routes
+	}
+	if node.handler != nil {
+		routes = append(routes, Route{Handler: node.handler, Params: params, Fallthrough: node.isFallthrough, Meta: node.meta, trail: copied})
 	}
+	for _, mh := range node.methods {
+		routes = append(routes, Route{Handler: mh.handler, Params: params, Fallthrough: node.isFallthrough, Method: mh.method, trail: copied})
+	}
+	return routes
+}
 
-	segment := segments[0]
-	remaining := segments[1:]
+// routeBlock holds the first route of a lookup together with its trail, so
+// that a lookup matching a single route allocates once.
+type routeBlock struct {
+	routes [1]Route
+	trail  [8]*Node
+}
 
-	routes := Routes{}
+// leaf returns the node holding the route.
+func (rt Route) leaf() *Node {
+	return rt.trail[len(rt.trail)-1]
+}
 
-	// Snapshot child pointers while holding the read lock to avoid
-	// iterating maps/slices that may be mutated by writers.
-	var staticChild *Node
-	if node.static_children != nil {
-		staticChild = node.static_children[segment]
+// appendRoutes appends newRoutes to routes, reusing newRoutes when routes is
+// still empty so a single match does not reallocate at every level.
+func appendRoutes(routes, newRoutes Routes) Routes {
+	if len(routes) == 0 {
+		return newRoutes
 	}
+	return append(routes, newRoutes...)
+}
 
-	var paramChildren []*Node
-	if len(node.params_children) > 0 {
-		paramChildren = make([]*Node, 0, len(node.params_children))
-		for _, child := range node.params_children {
-			paramChildren = append(paramChildren, child)
+// getValue recurses once per segment only where a node branches into param
+// or wildcard children; runs of purely static nodes are descended in a loop,
+// so stack depth is bounded by the number of branching nodes on the path
+// rather than its length. Tracing lookups recurse into every node so each
+// one is reported, once the routes below it are known.
+//
+// The lookup starts at the node at the end of trail, which every branch
+// extends in place for the nodes it descends through.
+func (r *RadixTree) getValue(trail []*Node, segments []string, params Params, lk lookup) (found Routes) {
+	if lk.trace != nil {
+		defer func() { lk.trace(wrap(slices.Clone(trail)), len(found) > 0) }()
+	}
+	node := trail[len(trail)-1]
+	for lk.trace == nil && len(segments) > 0 && len(node.params_children) == 0 && len(node.wildcard_children) == 0 {
+		child := r.staticChild(node, segments[0])
+		if child == nil {
+			return nil
 		}
+		node = child
+		trail = append(trail, node)
+		segments = segments[1:]
+	}
+
+	if lk.ctx != nil && lk.ctx.Err() != nil {
+		return nil
 	}
 
-	var wildcardChildren []*Node
-	if len(node.wildcard_children) > 0 {
-		wildcardChildren = make([]*Node, len(node.wildcard_children))
-		copy(wildcardChildren, node.wildcard_children)
+	// Branches that would capture more than MaxParams params are pruned.
+	canCapture := r.opts.MaxParams <= 0 || len(params) < r.opts.MaxParams
+
+	if len(segments) == 0 {
+		routes := lk.appendRoutes(nil, trail, params)
+		if !canCapture {
+			return routes
+		}
+		for _, kind := range r.opts.PriorityOrder {
+			switch kind {
+			case ParamNode:
+				if opt := node.optionalChild; opt != nil {
+					routes = lk.appendLeaf(routes, append(trail, opt), append(params[:len(params):len(params)], RouteParam{
+						Key:    opt.paramName,
+						Values: slices.Clone(opt.defaultValues),
+					}))
+				}
+			case Wildcard:
+				for _, child := range node.wildcard_children {
+					if child.minCapture() == 0 {
+						routes = lk.appendLeaf(routes, append(trail, child), append(params[:len(params):len(params)], RouteParam{
+							Key:    child.paramName,
+							Values: []string{},
+						}))
+					}
+				}
+			}
+		}
+		if lk.limit > 0 && len(routes) > lk.limit {
+			routes = routes[:lk.limit]
+		}
+		return routes
 	}
+	limit := lk.limit
+
+	segment := segments[0]
+	remaining := segments[1:]
 
-	// Try static children first (highest priority)
-	if staticChild != nil {
-		if newRoutes := r.getValue(staticChild, remaining, params); len(newRoutes) > 0 {
-			routes = append(routes, newRoutes...)
+	var routes Routes
+
+	// Published nodes are never mutated, so their children are read without
+	// locking. Param children are visited in key order.
+	staticChild := r.staticChild(node, segment)
+
+	wildcardChildren := node.wildcard_children
+
+	// budget returns the settings for a nested lookup, limited by how many
+	// routes have been collected so far.
+	budget := func() lookup {
+		nested := lk
+		if limit > 0 {
+			nested.limit = limit - len(routes)
 		}
+		return nested
 	}
 
-	// Try parameter children (medium priority)
-	if len(paramChildren) > 0 {
-		paramsRoutes := segments[:1]
-		for _, child := range paramChildren {
-			newParams := append(params, RouteParam{
-				Key:    child.paramName,
-				Values: paramsRoutes,
-			})
-			if newRoutes := r.getValue(child, remaining, newParams); len(newRoutes) > 0 {
-				routes = append(routes, newRoutes...)
-			}
+	// branchParams returns params for a branch about to append to them.
+	// Routes collected by earlier branches may reference the spare capacity
+	// of params, so once there are any, later branches get their own copy.
+	branchParams := func() Params {
+		if len(routes) > 0 {
+			return params[:len(params):len(params)]
 		}
+		return params
 	}
 
-	// Try wildcard child (lowest priority)
-	if len(wildcardChildren) > 0 {
-		for _, child := range wildcardChildren {
-			if child.handler != nil {
-				newParams := append(params, RouteParam{
+	// Child kinds are tried in PriorityOrder, by default static children
+	// first, then params and wildcards last.
+	for _, kind := range r.opts.PriorityOrder {
+		switch kind {
+		case Static:
+			if staticChild == nil {
+				continue
+			}
+			if limit > 0 && len(routes) >= limit {
+				return routes
+			}
+			found := r.getValue(append(trail, staticChild), remaining, branchParams(), budget())
+			routes = appendRoutes(routes, found)
+			if r.opts.PreferStatic && len(found) > 0 {
+				return routes
+			}
+		case ParamNode:
+			if !canCapture {
+				continue
+			}
+			paramsRoutes := r.paramValues(segments[:1])
+			for _, key := range node.params_keys {
+				child := node.params_children[key]
+				if child.constraint != nil && !child.constraint.MatchString(segment) {
+					continue
+				}
+				if limit > 0 && len(routes) >= limit {
+					return routes
+				}
+				newParams := append(branchParams(), RouteParam{
 					Key:    child.paramName,
-					Values: segments,
+					Values: paramsRoutes,
 				})
-				routes = append(routes, Route{Handler: child.handler, Params: newParams})
+				routes = appendRoutes(routes, r.getValue(append(trail, child), remaining, newParams, budget()))
+			}
+		case Wildcard:
+			if !canCapture {
+				continue
+			}
+			for _, child := range wildcardChildren {
+				if limit > 0 && len(routes) >= limit {
+					return routes
+				}
+				if len(segments) < child.minSegments {
+					continue
+				}
+				newParams := append(branchParams(), RouteParam{
+					Key:    child.paramName,
+					Values: r.paramValues(segments),
+				})
+				if r.opts.RawWildcards {
+					newParams[len(newParams)-1].Raw = strings.Join(segments, "/")
+				}
+				routes = lk.appendLeaf(routes, append(trail, child), newParams)
 			}
 		}
 	}
 
+	if limit > 0 && len(routes) > limit {
+		routes = routes[:limit]
+	}
 	return routes
 }
 
-func (r *RadixTree) deleteRoute(node *Node, path []string) error {
+// deleteRoute removes the handler at path below node, which the write in
+// progress must own, copying the nodes it descends through. Sizes are only
+// decremented while unwinding from a successful removal, so a failed delete
+// leaves every counter on the path untouched.
+func (r *RadixTree) deleteRoute(node *Node, method string, path []string) error {
 	if len(path) == 0 {
+		if method != "" {
+			i, found := slices.BinarySearchFunc(node.methods, method, compareMethod)
+			if !found {
+				return fmt.Errorf("%w: no %s handler at %q", ErrPathNotFound, method, node.path)
+			}
+			node.methods = slices.Delete(node.methods, i, i+1)
+			if len(node.methods) == 0 {
+				node.methods = nil
+			}
+			node.nodeSize--
+			return nil
+		}
 		if node.handler != nil {
 			node.handler = nil
+			node.isFallthrough = false
+			node.meta = nil
 			node.nodeSize--
 			return nil
 		}
-		return fmt.Errorf("path cannot be empty")
+		return fmt.Errorf("%w: no handler at %q", ErrPathNotFound, node.path)
 	}
 	segment := path[0]
 	remaining := path[1:]
 
-	var child *Node
-	if strings.HasPrefix(segment, "*") {
-		for _, wc := range node.wildcard_children {
-			if wc.path == segment {
-				child = wc
-				break
-			}
-		}
-	} else if strings.HasPrefix(segment, ":") {
-		if node.params_children != nil {
-			child = node.params_children[segment[1:]]
-		}
-	} else {
-		if node.static_children != nil {
-			child = node.static_children[segment]
-		}
-	}
-
+	child := r.findChild(node, segment)
 	if child == nil {
-		return fmt.Errorf("path not found")
+		return fmt.Errorf("%w: %q", ErrPathNotFound, segment)
 	}
+	child = r.ownChild(node, child)
 
-	err := r.deleteRoute(child, remaining)
+	err := r.deleteRoute(child, method, remaining)
 	if err != nil {
 		return err
 	}
 
+	if len(remaining) == 0 && node.optionalChild == child && child.handler == nil {
+		node.optionalChild = nil
+		child.defaultValues = nil
+	}
 	if child.nodeSize == 0 {
-		switch child.nodeType {
-		case Static:
-			delete(node.static_children, child.path)
-			if len(node.static_children) == 0 {
-				node.static_children = nil
-			}
-		case ParamNode:
-			delete(node.params_children, child.paramName)
-			if len(node.params_children) == 0 {
-				node.params_children = nil
-			}
-		case Wildcard:
-			for i, wc := range node.wildcard_children {
-				if wc == child {
-					node.wildcard_children = append(node.wildcard_children[:i], node.wildcard_children[i+1:]...)
-					break
-				}
-			}
-		}
+		node.removeChild(child)
 	}
 
 	node.nodeSize--
 	return nil
 }
+
+// removeChild unlinks child from the child collection of n.
+func (n *Node) removeChild(child *Node) {
+	switch child.nodeType {
+	case Static:
+		delete(n.static_children, child.path)
+		if i, found := slices.BinarySearch(n.static_keys, child.path); found {
+			n.static_keys = slices.Delete(n.static_keys, i, i+1)
+		}
+		if len(n.static_children) == 0 {
+			n.static_children = nil
+			n.static_keys = nil
+		}
+	case ParamNode:
+		delete(n.params_children, child.paramName)
+		if i, found := slices.BinarySearch(n.params_keys, child.paramName); found {
+			n.params_keys = slices.Delete(n.params_keys, i, i+1)
+		}
+		if len(n.params_children) == 0 {
+			n.params_children = nil
+			n.params_keys = nil
+		}
+		if n.optionalChild == child {
+			n.optionalChild = nil
+		}
+	case Wildcard:
+		for i, wc := range n.wildcard_children {
+			if wc == child {
+				n.wildcard_children = append(n.wildcard_children[:i], n.wildcard_children[i+1:]...)
+				break
+			}
+		}
+	}
+}
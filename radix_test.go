@@ -1,8 +1,11 @@
 package radix_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math/rand"
+	"regexp"
 	"testing"
 
 	radix "github.com/saeedsamimi/router-radix-tree"
@@ -313,6 +316,29 @@ func TestConflictingWildcardRoutes(t *testing.T) {
 	}
 }
 
+func TestWildcardSiblingOrder(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"files", "*second"}, "second")
+	tree.Add([]string{"files", "*first"}, "first")
+	tree.Add([]string{"files", "*second"}, "second_again")
+
+	for i := 0; i < 20; i++ {
+		routes := tree.Get([]string{"files", "a"})
+		assert.Len(t, routes, 3)
+		assert.Equal(t, "second", routes[0].Handler.(string))
+		assert.Equal(t, "first", routes[1].Handler.(string))
+		assert.Equal(t, "second_again", routes[2].Handler.(string))
+	}
+
+	tree.Delete([]string{"files", "*second"})
+	tree.Add([]string{"files", "*second"}, "readded")
+	routes := tree.Get([]string{"files", "a"})
+	assert.Len(t, routes, 3)
+	assert.Equal(t, "first", routes[0].Handler.(string))
+	assert.Equal(t, "second_again", routes[1].Handler.(string))
+	assert.Equal(t, "readded", routes[2].Handler.(string), "Re-added wildcard should move last")
+}
+
 func TestEmptyParameterName(t *testing.T) {
 	tree := radix.NewRadixTree()
 	_, err := tree.Add([]string{"users", ":"}, "handler")
@@ -364,6 +390,28 @@ func TestTreeInsertion1(t *testing.T) {
 	assert.Equal(t, parent2, nw1)
 }
 
+func TestLiveWrappers(t *testing.T) {
+	tree := radix.NewRadixTree()
+	users, _ := tree.Add([]string{"users"}, "users")
+	root := tree.Root()
+	leaf, _, _ := tree.GetLeaf([]string{"users"})
+
+	tree.Add([]string{"users", ":id"}, "user_show")
+	assert.Equal(t, uint32(2), users.Size(), "Wrappers from Add should see later writes")
+	assert.Len(t, root.Children()[0].Children(), 1)
+	assert.Equal(t, uint32(1), leaf.Size(), "Wrappers from lookups should keep the tree they saw")
+
+	tree.DeleteSubtree([]string{"users"})
+	assert.Zero(t, users.Size(), "A removed node should read as empty")
+	handler, ok := users.Handler()
+	assert.Nil(t, handler)
+	assert.False(t, ok)
+	assert.Equal(t, []string{"users"}, users.Path())
+	assert.Empty(t, root.Children())
+	handler, _ = leaf.Handler()
+	assert.Equal(t, "users", handler)
+}
+
 func TestTreeInsertion2(t *testing.T) {
 	tree := radix.NewRadixTree()
 	nw1, _ := tree.Add([]string{"files", "*filepath"}, "files")
@@ -515,6 +563,43 @@ func TestMultipleMatchingRoutes(t *testing.T) {
 	}
 }
 
+func TestMultipleMatchingParamsDoNotAlias(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{":a", ":b", ":c", ":x"}, "x")
+	tree.Add([]string{":a", ":b", ":c", ":y"}, "y")
+	tree.Add([]string{":a", ":b", ":c", "*rest"}, "rest")
+	tree.Add([]string{"api", ":version", ":resource"}, "resource")
+	tree.Add([]string{"api", ":version", ":collection"}, "collection")
+
+	assertRoutes := func(routes radix.Routes, expected map[string]radix.Params) {
+		assert.Len(t, routes, len(expected))
+		for _, route := range routes {
+			assert.Equal(t, expected[route.Handler.(string)], route.Params, "Params for handler %s", route.Handler)
+		}
+	}
+
+	common := radix.Params{
+		{Key: "a", Values: []string{"1"}},
+		{Key: "b", Values: []string{"2"}},
+		{Key: "c", Values: []string{"3"}},
+	}
+	expected := map[string]radix.Params{
+		"x":    append(common[:3:3], radix.RouteParam{Key: "x", Values: []string{"4"}}),
+		"y":    append(common[:3:3], radix.RouteParam{Key: "y", Values: []string{"4"}}),
+		"rest": append(common[:3:3], radix.RouteParam{Key: "rest", Values: []string{"4"}}),
+	}
+	assertRoutes(tree.Get([]string{"1", "2", "3", "4"}), expected)
+	assertRoutes(tree.GetInto([]string{"1", "2", "3", "4"}, make(radix.Params, 0, 16)), expected)
+
+	expected = map[string]radix.Params{
+		"resource":   {{Key: "version", Values: []string{"v1"}}, {Key: "resource", Values: []string{"users"}}},
+		"collection": {{Key: "version", Values: []string{"v1"}}, {Key: "collection", Values: []string{"users"}}},
+	}
+	assertRoutes(tree.Get([]string{"api", "v1", "users"}), expected)
+	assertRoutes(tree.GetInto([]string{"api", "v1", "users"}, make(radix.Params, 0, 16)), expected)
+}
+
 // TestParamsGet tests the radix.Params.Get method
 func TestParamsGet(t *testing.T) {
 	params := radix.Params{
@@ -542,6 +627,57 @@ func TestParamsGet(t *testing.T) {
 	assert.Equal(t, len(value), 0, "Should return nil slice for non-existing parameter")
 }
 
+func TestParamsGetOne(t *testing.T) {
+	params := radix.Params{
+		{Key: "id", Values: []string{"123"}},
+		{Key: "filepath", Values: []string{"docs", "guide", "intro.md"}},
+	}
+
+	value, found := params.GetOne("id")
+	assert.True(t, found, "Should find existing parameter")
+	assert.Equal(t, "123", value)
+
+	value, found = params.GetOne("filepath")
+	assert.True(t, found, "Should find wildcard parameter")
+	assert.Equal(t, "docs/guide/intro.md", value, "Wildcard segments should be joined")
+
+	value, found = params.GetOne("nonexistent")
+	assert.False(t, found, "Should not find non-existing parameter")
+	assert.Equal(t, "", value)
+}
+
+func TestParamsGetAll(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"a", ":x", "b", ":x"}, "handler")
+
+	routes := tree.Get([]string{"a", "1", "b", "2"})
+	assert.Len(t, routes, 1)
+
+	values, _ := routes[0].Params.Get("x")
+	assert.Equal(t, []string{"1"}, values, "Get should return the first match")
+	assert.Equal(t, [][]string{{"1"}, {"2"}}, routes[0].Params.GetAll("x"))
+	assert.Nil(t, routes[0].Params.GetAll("missing"))
+}
+
+func TestParamsGetPath(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"files", ":owner", "*filepath"}, "files")
+
+	routes := tree.Get([]string{"files", "alice", "a", "b", "c"})
+	assert.Len(t, routes, 1)
+
+	path, found := routes[0].Params.GetPath("filepath")
+	assert.True(t, found)
+	assert.Equal(t, "a/b/c", path)
+
+	path, found = routes[0].Params.GetPath("owner")
+	assert.True(t, found)
+	assert.Equal(t, "alice", path, "Single segments are returned as is")
+
+	_, found = routes[0].Params.GetPath("missing")
+	assert.False(t, found)
+}
+
 func TestDeletion(t *testing.T) {
 	tree := radix.NewRadixTree()
 
@@ -579,6 +715,26 @@ func TestDeletion(t *testing.T) {
 	assert.Equal(t, tree.Size(), uint32(3), "Tree size should remain the same")
 }
 
+func TestGetInto(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"users", ":id", "posts", ":post_id"}, "user_post_show")
+	tree.Add([]string{"files", "*filepath"}, "files")
+
+	buf := make(radix.Params, 0, 4)
+	routes := tree.GetInto([]string{"users", "1", "posts", "2"}, buf)
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"1"}}, {Key: "post_id", Values: []string{"2"}}}, routes[0].Params)
+	assert.Equal(t, &buf[:1][0], &routes[0].Params[0], "Params should be stored in the caller's buffer")
+
+	routes = tree.GetInto([]string{"files", "a", "b"}, buf)
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{{Key: "filepath", Values: []string{"a", "b"}}}, routes[0].Params)
+
+	assert.Len(t, tree.GetInto([]string{"missing"}, buf), 0)
+	assert.Len(t, tree.GetInto([]string{"users", "1", "posts", "2"}, nil), 1, "A nil buffer should behave like Get")
+}
+
 func BenchmarkStaticRoutes(b *testing.B) {
 	tree := radix.NewRadixTree()
 
@@ -619,6 +775,20 @@ func BenchmarkParameterRoutes(b *testing.B) {
 	}
 }
 
+func BenchmarkParameterRoutesGetInto(b *testing.B) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"users", ":id", "posts"}, "user_posts")
+	tree.Add([]string{"users", ":id", "posts", ":post_id"}, "user_post_show")
+	tree.Add([]string{"articles", ":slug", "comments", ":comment_id"}, "article_comment")
+
+	buf := make(radix.Params, 0, 8)
+	for b.Loop() {
+		tree.GetInto([]string{"users", "123", "posts", "456"}, buf)
+	}
+}
+
 func BenchmarkWildcardRoutes(b *testing.B) {
 	tree := radix.NewRadixTree()
 
@@ -631,40 +801,40 @@ func BenchmarkWildcardRoutes(b *testing.B) {
 	}
 }
 
+// mixedRoutes is a realistic set of routes shared by tests and benchmarks.
+var mixedRoutes = []struct {
+	path    []string
+	handler string
+}{
+	{[]string{}, "home"},
+	{[]string{"api"}, "api_root"},
+	{[]string{"api", "v1"}, "api_v1"},
+	{[]string{"api", "v1", "users"}, "users_index"},
+	{[]string{"api", "v1", "users", ":id"}, "user_show"},
+	{[]string{"api", "v1", "users", ":id", "posts"}, "user_posts"},
+	{[]string{"api", "v1", "users", ":id", "posts", ":post_id"}, "user_post_show"},
+	{[]string{"api", "v1", "posts"}, "posts_index"},
+	{[]string{"api", "v1", "posts", ":id"}, "post_show"},
+	{[]string{"api", "v1", "posts", ":id", "comments"}, "post_comments"},
+	{[]string{"api", "v1", "posts", ":id", "comments", ":comment_id"}, "post_comment_show"},
+	{[]string{"profile", ":username"}, "profile_show"},
+	{[]string{"profile", ":username", "settings"}, "profile_settings"},
+	{[]string{"profile", ":username", ":id", "hello"}, "profile_hello"},
+	{[]string{"profile", ":username", "pic", "*picture"}, "profile_picture"},
+	{[]string{"search", "*"}, "search"},
+	{[]string{"search"}, "search"},
+	{[]string{"admin"}, "admin_root"},
+	{[]string{"admin", "users"}, "admin_users"},
+	{[]string{"admin", "posts"}, "admin_posts"},
+	{[]string{"admin", "*path"}, "admin_catch_all"},
+	{[]string{"files", "*filepath"}, "serve_files"},
+	{[]string{"static", "*filename"}, "static_files"},
+}
+
 func BenchmarkMixedRoutes(b *testing.B) {
 	tree := radix.NewRadixTree()
 
-	// Add a realistic set of routes
-	routes := []struct {
-		path    []string
-		handler string
-	}{
-		{[]string{}, "home"},
-		{[]string{"api"}, "api_root"},
-		{[]string{"api", "v1"}, "api_v1"},
-		{[]string{"api", "v1", "users"}, "users_index"},
-		{[]string{"api", "v1", "users", ":id"}, "user_show"},
-		{[]string{"api", "v1", "users", ":id", "posts"}, "user_posts"},
-		{[]string{"api", "v1", "users", ":id", "posts", ":post_id"}, "user_post_show"},
-		{[]string{"api", "v1", "posts"}, "posts_index"},
-		{[]string{"api", "v1", "posts", ":id"}, "post_show"},
-		{[]string{"api", "v1", "posts", ":id", "comments"}, "post_comments"},
-		{[]string{"api", "v1", "posts", ":id", "comments", ":comment_id"}, "post_comment_show"},
-		{[]string{"profile", ":username"}, "profile_show"},
-		{[]string{"profile", ":username", "settings"}, "profile_settings"},
-		{[]string{"profile", ":username", ":id", "hello"}, "profile_hello"},
-		{[]string{"profile", ":username", "pic", "*picture"}, "profile_picture"},
-		{[]string{"search", "*"}, "search"},
-		{[]string{"search"}, "search"},
-		{[]string{"admin"}, "admin_root"},
-		{[]string{"admin", "users"}, "admin_users"},
-		{[]string{"admin", "posts"}, "admin_posts"},
-		{[]string{"admin", "*path"}, "admin_catch_all"},
-		{[]string{"files", "*filepath"}, "serve_files"},
-		{[]string{"static", "*filename"}, "static_files"},
-	}
-
-	for _, route := range routes {
+	for _, route := range mixedRoutes {
 		tree.Add(route.path, route.handler)
 	}
 
@@ -710,3 +880,961 @@ func BenchmarkManyRoutes(b *testing.B) {
 		}
 	}
 }
+
+func TestFallthroughRoutes(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	_, err := tree.AddFallthrough([]string{"files", ":name"}, "file_param")
+	assert.Nil(t, err)
+	tree.Add([]string{"files", "*filepath"}, "file_wildcard")
+
+	routes := tree.Get([]string{"files", "report.pdf"})
+	assert.Len(t, routes, 2)
+	assert.True(t, routes[0].Fallthrough, "Param route should be marked as fallthrough")
+	assert.False(t, routes[1].Fallthrough, "Wildcard route should not be marked as fallthrough")
+
+	route, found := tree.GetOne([]string{"files", "report.pdf"}, func(route radix.Route) bool {
+		return false
+	})
+	assert.True(t, found)
+	assert.Equal(t, "file_wildcard", route.Handler.(string), "Declined fallthrough route should yield to the wildcard")
+	assert.Equal(t, radix.Params{{Key: "filepath", Values: []string{"report.pdf"}}}, route.Params)
+
+	route, found = tree.GetOne([]string{"files", "report.pdf"}, func(route radix.Route) bool {
+		return true
+	})
+	assert.True(t, found)
+	assert.Equal(t, "file_param", route.Handler.(string), "Accepted fallthrough route should be returned")
+
+	_, found = tree.GetOne([]string{"unknown"}, nil)
+	assert.False(t, found)
+}
+
+func TestAddWithMeta(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	assert.Nil(t, tree.AddWithMeta([]string{"users", ":id"}, "user", "users.show"))
+	assert.Nil(t, tree.AddMethod("GET", []string{"users", ":id"}, "get_user"))
+
+	routes := tree.Get([]string{"users", "42"})
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "users.show", routes[0].Meta)
+	assert.Nil(t, routes[1].Meta, "Method handlers should have no metadata")
+
+	assert.ErrorIs(t, tree.AddWithMeta([]string{"users", ":id"}, "other", "other"), radix.ErrHandlerExists)
+
+	assert.Nil(t, tree.Delete([]string{"users", ":id"}))
+	tree.Add([]string{"users", ":id"}, "user")
+	routes = tree.Get([]string{"users", "42"})
+	assert.Equal(t, "user", routes[0].Handler)
+	assert.Nil(t, routes[0].Meta, "Delete should clear the metadata")
+}
+
+func TestFloorChild(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"api", "v1"}, "api_v1")
+	nw, _ := tree.Add([]string{"api", "v2"}, "api_v2")
+	api, _ := nw.Parent()
+
+	child, found := api.FloorChild("v3")
+	assert.True(t, found, "Floor of v3 should exist")
+	assert.Equal(t, "v2", child.PathName())
+
+	child, found = api.FloorChild("v1")
+	assert.True(t, found, "Exact key should be its own floor")
+	assert.Equal(t, "v1", child.PathName())
+
+	_, found = api.FloorChild("v0")
+	assert.False(t, found, "No child should be below v0")
+
+	tree.Delete([]string{"api", "v2"})
+	nw, _ = tree.Add([]string{"api", "v0"}, "api_v0")
+	api, _ = nw.Parent()
+	child, found = api.FloorChild("v3")
+	assert.True(t, found)
+	assert.Equal(t, "v1", child.PathName(), "Deleted child should not be returned")
+}
+
+func TestClear(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{}, "root")
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"files", "*filepath"}, "files")
+	assert.Equal(t, uint32(3), tree.Size())
+
+	tree.Clear()
+	assert.Zero(t, tree.Size(), "Cleared tree should be empty")
+	assert.Len(t, tree.Get([]string{}), 0, "Root handler should be removed")
+	assert.Len(t, tree.Get([]string{"users", "123"}), 0, "Param route should be removed")
+	assert.Len(t, tree.Get([]string{"files", "a", "b"}), 0, "Wildcard route should be removed")
+
+	_, err := tree.Add([]string{"users", ":id"}, "user_show")
+	assert.Nil(t, err, "Routes should be re-registrable after Clear")
+	assert.Equal(t, uint32(1), tree.Size())
+}
+
+func TestRouteMethodEmptyForMethodlessLookup(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"files", "*filepath"}, "files")
+
+	routes := tree.Get([]string{"users", "123"})
+	assert.Len(t, routes, 1)
+	assert.Empty(t, routes[0].Method)
+
+	routes = tree.Get([]string{"files", "a", "b"})
+	assert.Len(t, routes, 1)
+	assert.Empty(t, routes[0].Method)
+}
+
+func TestParameterConstraints(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	numeric := regexp.MustCompile("^[0-9]+$")
+	_, err := tree.AddWithConstraints([]string{"users", ":id"}, "user_show", map[string]*regexp.Regexp{"id": numeric})
+	assert.Nil(t, err)
+	_, err = tree.AddWithConstraints([]string{"users", ":id", "posts"}, "user_posts", map[string]*regexp.Regexp{"id": numeric})
+	assert.Nil(t, err, "Same constraint should be accepted on a shared param node")
+	tree.Add([]string{"files", ":name"}, "file_param")
+	tree.Add([]string{"files", "*filepath"}, "file_wildcard")
+
+	routes := tree.Get([]string{"users", "123"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "user_show", routes[0].Handler.(string))
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"123"}}}, routes[0].Params)
+
+	routes = tree.Get([]string{"users", "abc"})
+	assert.Len(t, routes, 0, "Constrained param should not match a non-numeric segment")
+
+	routes = tree.Get([]string{"users", "abc", "posts"})
+	assert.Len(t, routes, 0, "Constrained param should not match a non-numeric segment")
+
+	_, err = tree.AddWithConstraints([]string{"users", ":id"}, "other", map[string]*regexp.Regexp{"id": regexp.MustCompile("^[a-z]+$")})
+	assert.True(t, errors.Is(err, radix.ErrConstraintConflict), "Different constraint on the same param should conflict")
+
+	_, err = tree.AddWithConstraints([]string{"files", ":name"}, "other", map[string]*regexp.Regexp{"missing": numeric})
+	assert.NotNil(t, err, "Constraint for an unknown parameter should be rejected")
+}
+
+func TestParameterConstraintFallsThroughToWildcard(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.AddWithConstraints([]string{"files", ":id"}, "file_id", map[string]*regexp.Regexp{"id": regexp.MustCompile("^[0-9]+$")})
+	tree.Add([]string{"files", "*filepath"}, "file_wildcard")
+
+	routes := tree.Get([]string{"files", "42"})
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "file_id", routes[0].Handler.(string))
+
+	routes = tree.Get([]string{"files", "readme"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "file_wildcard", routes[0].Handler.(string), "Rejected param should fall through to the wildcard")
+}
+
+func TestGetDeepest(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"files", "*all"}, "files_all")
+	tree.Add([]string{"files", "a", "b"}, "files_a_b")
+	tree.Add([]string{"docs", "*all"}, "docs_all")
+	tree.Add([]string{"docs", ":section", ":page"}, "docs_page")
+
+	route, found := tree.GetDeepest([]string{"files", "a", "b"})
+	assert.True(t, found)
+	assert.Equal(t, "files_a_b", route.Handler.(string))
+
+	route, found = tree.GetDeepest([]string{"files", "a", "c"})
+	assert.True(t, found)
+	assert.Equal(t, "files_all", route.Handler.(string))
+
+	route, found = tree.GetDeepest([]string{"docs", "intro", "setup"})
+	assert.True(t, found)
+	assert.Equal(t, "docs_page", route.Handler.(string), "Deep param route should beat the shallow wildcard")
+	assert.Equal(t, radix.Params{{Key: "section", Values: []string{"intro"}}, {Key: "page", Values: []string{"setup"}}}, route.Params)
+
+	_, found = tree.GetDeepest([]string{"unknown"})
+	assert.False(t, found)
+}
+
+func TestGetByPattern(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{}, "root")
+	tree.Add([]string{"api", ":version"}, "api_version")
+	tree.Add([]string{"api", "*path"}, "api_catch_all")
+
+	patterns := tree.GetByPattern([]string{"api", "v1"})
+	assert.Equal(t, map[string]radix.Params{
+		"/api/:version": {{Key: "version", Values: []string{"v1"}}},
+		"/api/*path":    {{Key: "path", Values: []string{"v1"}}},
+	}, patterns)
+
+	patterns = tree.GetByPattern([]string{})
+	assert.Equal(t, map[string]radix.Params{"/": nil}, patterns)
+
+	assert.Empty(t, tree.GetByPattern([]string{"unknown"}))
+}
+
+func TestClone(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{}, "root")
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"users", ":id", "posts"}, "user_posts")
+	tree.Add([]string{"files", "*filepath"}, "files")
+
+	clone := tree.Clone()
+	assert.Equal(t, tree.Size(), clone.Size())
+	assert.Equal(t, tree.Count(), clone.Count())
+
+	nw, err := clone.Add([]string{"users", ":id", "settings"}, "user_settings")
+	assert.Nil(t, err)
+	parent, _ := nw.Parent()
+	grandparent, _ := parent.Parent()
+	assert.Equal(t, ":id", parent.PathName())
+	assert.Equal(t, "users", grandparent.PathName(), "Parent links should point into the clone")
+
+	assert.Nil(t, clone.Delete([]string{"files", "*filepath"}))
+	assert.Equal(t, uint32(4), clone.Size())
+
+	assert.Equal(t, uint32(4), tree.Size(), "Original should be unaffected by changes to the clone")
+	assert.Len(t, tree.Get([]string{"users", "1", "settings"}), 0)
+	assert.Len(t, tree.Get([]string{"files", "a"}), 1)
+
+	routes := clone.Get([]string{"users", "1", "posts"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"1"}}}, routes[0].Params)
+}
+
+func TestWritesCopyOnlyTheirPath(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"files", "*filepath"}, "files")
+
+	clone := tree.Clone()
+	assert.True(t, tree.SameNodeForTest(clone, []string{"users", ":id"}), "Clone should share every node until a write")
+
+	clone.Add([]string{"users", ":id", "settings"}, "user_settings")
+	assert.False(t, tree.SameNodeForTest(clone, []string{"users"}))
+	assert.False(t, tree.SameNodeForTest(clone, []string{"users", ":id"}))
+	assert.True(t, tree.SameNodeForTest(clone, []string{"files"}), "Nodes off the written path should be shared")
+	assert.True(t, tree.SameNodeForTest(clone, []string{"files", "*filepath"}))
+
+	assert.Nil(t, tree.Delete([]string{"files", "*filepath"}))
+	assert.Len(t, clone.Get([]string{"files", "a"}), 1)
+	assert.Len(t, tree.Get([]string{"users", "1", "settings"}), 0)
+}
+
+func TestGetLatest(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"plugins", ":name"}, "core")
+	tree.Add([]string{"plugins", "*rest"}, "fallback")
+	tree.Add([]string{"plugins", "search"}, "search")
+	tree.Add([]string{"plugins", ":plugin"}, "override")
+
+	route, found := tree.GetLatest([]string{"plugins", "search"})
+	assert.True(t, found)
+	assert.Equal(t, "override", route.Handler.(string))
+	assert.Equal(t, radix.Params{{Key: "plugin", Values: []string{"search"}}}, route.Params)
+
+	tree.Delete([]string{"plugins", "*rest"})
+	tree.Add([]string{"plugins", "*rest"}, "fallback")
+	route, _ = tree.GetLatest([]string{"plugins", "search"})
+	assert.Equal(t, "fallback", route.Handler.(string), "Re-adding a route makes it the newest")
+
+	_, found = tree.GetLatest([]string{"other"})
+	assert.False(t, found)
+}
+
+func TestGetLatestMountedRoutes(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"api", ":resource"}, "generic")
+
+	sub := radix.NewRadixTree()
+	sub.Add([]string{"users"}, "users")
+	tree.Mount([]string{"api"}, sub)
+	tree.Add([]string{"api", "*rest"}, "rest")
+
+	route, _ := tree.GetLatest([]string{"api", "users"})
+	assert.Equal(t, "rest", route.Handler.(string))
+	tree.Delete([]string{"api", "*rest"})
+	route, _ = tree.GetLatest([]string{"api", "users"})
+	assert.Equal(t, "users", route.Handler.(string), "Mounted routes count as registered when mounted")
+
+	clone := tree.Clone()
+	clone.Add([]string{"api", ":other"}, "newer")
+	route, _ = clone.GetLatest([]string{"api", "users"})
+	assert.Equal(t, "newer", route.Handler.(string), "Clones keep numbering after the original")
+}
+
+func TestNodeWrapperSequence(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	var last uint64
+	for _, path := range [][]string{{"a"}, {"a", ":id"}, {"b", "*rest"}, {"a", ":id", "c"}} {
+		nw, err := tree.Add(path, "handler")
+		assert.Nil(t, err)
+		assert.Greater(t, nw.Sequence(), last, "Sequence should increase with every Add")
+		last = nw.Sequence()
+	}
+
+	_, err := tree.Add([]string{"a"}, "again")
+	assert.ErrorIs(t, err, radix.ErrHandlerExists)
+	nw, _ := tree.Add([]string{"d"}, "handler")
+	assert.Equal(t, last+1, nw.Sequence(), "Failed adds should not use up a number")
+
+	tree.AddMethod("GET", []string{"a"}, "get")
+	leaf, _, _ := tree.GetLeaf([]string{"a"})
+	assert.Equal(t, nw.Sequence()+1, leaf.Sequence())
+
+	assert.Equal(t, uint64(0), tree.Root().Sequence())
+	assert.Equal(t, leaf.Sequence(), tree.Stats().LatestSequence)
+}
+
+func TestGetWithAncestor(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"admin"}, "admin_group")
+	tree.Add([]string{"admin", "users"}, "users")
+	tree.Add([]string{"orgs", ":org"}, "org_group")
+	tree.Add([]string{"orgs", ":org", "teams", ":team"}, "team")
+	tree.Add([]string{"public", "about"}, "about")
+
+	exact, ancestor, found := tree.GetWithAncestor([]string{"admin", "users"})
+	assert.True(t, found)
+	assert.Equal(t, "users", exact.Handler.(string))
+	assert.Equal(t, "admin_group", ancestor.Handler.(string))
+
+	exact, ancestor, found = tree.GetWithAncestor([]string{"orgs", "acme", "teams", "core"})
+	assert.True(t, found)
+	assert.Equal(t, "team", exact.Handler.(string))
+	assert.Equal(t, radix.Params{
+		{Key: "org", Values: []string{"acme"}},
+		{Key: "team", Values: []string{"core"}},
+	}, exact.Params)
+	assert.Equal(t, "org_group", ancestor.Handler.(string), "Intermediate nodes without handlers are skipped")
+	assert.Equal(t, radix.Params{{Key: "org", Values: []string{"acme"}}}, ancestor.Params)
+
+	exact, ancestor, found = tree.GetWithAncestor([]string{"public", "about"})
+	assert.True(t, found)
+	assert.Equal(t, "about", exact.Handler.(string))
+	assert.Nil(t, ancestor.Handler)
+
+	exact, ancestor, found = tree.GetWithAncestor([]string{"admin"})
+	assert.True(t, found)
+	assert.Equal(t, "admin_group", exact.Handler.(string))
+	assert.Nil(t, ancestor.Handler, "The match itself is not its own ancestor")
+
+	_, _, found = tree.GetWithAncestor([]string{"missing"})
+	assert.False(t, found)
+}
+
+func TestGetWithAncestorRoot(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.SetRoot("root")
+	tree.AddOptional([]string{"docs", ":page"}, "page", "index")
+
+	exact, ancestor, found := tree.GetWithAncestor([]string{"docs"})
+	assert.True(t, found)
+	assert.Equal(t, radix.Params{{Key: "page", Values: []string{"index"}}}, exact.Params)
+	assert.Equal(t, "root", ancestor.Handler.(string))
+	assert.Empty(t, ancestor.Params)
+}
+
+func TestGetTraced(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", "me"}, "me")
+	tree.Add([]string{"users", ":id", "posts"}, "posts")
+	tree.Add([]string{"users", "*rest"}, "rest")
+
+	visited := map[string]bool{}
+	routes := tree.GetTraced([]string{"users", "42", "posts"}, func(node *radix.NodeWrapper, matched bool) {
+		visited[node.Pattern()] = matched
+	})
+	assert.Equal(t, []string{"posts", "rest"}, handlers(routes))
+	assert.Equal(t, map[string]bool{
+		"/":                true,
+		"/users":           true,
+		"/users/:id":       true,
+		"/users/:id/posts": true,
+		"/users/*rest":     true,
+	}, visited)
+
+	visited = map[string]bool{}
+	routes = tree.GetTraced([]string{"users", "me", "extra"}, func(node *radix.NodeWrapper, matched bool) {
+		visited[node.Pattern()] = matched
+	})
+	assert.Equal(t, []string{"rest"}, handlers(routes))
+	assert.Equal(t, map[string]bool{
+		"/":            true,
+		"/users":       true,
+		"/users/me":    false,
+		"/users/:id":   false,
+		"/users/*rest": true,
+	}, visited)
+}
+
+func TestGetTracedOrder(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"a", "b", "c"}, "abc")
+
+	var patterns []string
+	tree.GetTraced([]string{"a", "b", "c"}, func(node *radix.NodeWrapper, matched bool) {
+		assert.True(t, matched)
+		patterns = append(patterns, node.Pattern())
+	})
+	assert.Equal(t, []string{"/a/b/c", "/a/b", "/a", "/"}, patterns, "Static runs should be reported node by node")
+}
+
+func TestMissReturnsNil(t *testing.T) {
+	static := radix.NewRadixTree()
+	static.Add([]string{"a", "b"}, "ab")
+	assert.Nil(t, static.Get([]string{"a"}))
+	assert.Nil(t, static.Get([]string{"x"}))
+
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id", "posts"}, "posts")
+	tree.AddMethod("GET", []string{"files", "*path"}, "files")
+	miss := []string{"users", "42"}
+
+	assert.Nil(t, tree.Get(miss))
+	assert.Nil(t, tree.Get([]string{"other"}))
+	assert.Nil(t, tree.GetN(miss, 1))
+	assert.Nil(t, tree.GetInto(miss, nil))
+	assert.Nil(t, tree.GetMethod("POST", []string{"files", "x"}))
+	assert.Nil(t, tree.GetBatch([][]string{miss})[0])
+	assert.Nil(t, tree.GetTraced(miss, func(*radix.NodeWrapper, bool) {}))
+	routes, err := tree.GetContext(context.Background(), miss)
+	assert.Nil(t, err)
+	assert.Nil(t, routes)
+	routes, _ = tree.GetWithTrailingSlash(append(miss, ""))
+	assert.Nil(t, routes)
+	routes, err = tree.GetURL("/users/42")
+	assert.Nil(t, err)
+	assert.Nil(t, routes)
+
+	typed := radix.NewTree[string]()
+	assert.Nil(t, typed.Get([]string{"missing"}))
+}
+
+func TestGetBatch(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id"}, "user")
+	tree.Add([]string{"users", "me"}, "me")
+	tree.Add([]string{"static"}, "static")
+
+	paths := [][]string{
+		{"users", "me"},
+		{"missing"},
+		{"users", "42"},
+		{"static"},
+	}
+	results := tree.GetBatch(paths)
+	assert.Len(t, results, len(paths))
+	for i, path := range paths {
+		assert.Equal(t, tree.Get(path), results[i], "Result %d should match Get", i)
+	}
+	assert.Empty(t, results[1])
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, results[2][0].Params)
+
+	assert.Empty(t, tree.GetBatch(nil))
+
+	static := radix.NewRadixTree()
+	static.Add([]string{"a", "b"}, "ab")
+	results = static.GetBatch([][]string{{"a", "b"}, {"a"}})
+	assert.Equal(t, []string{"ab"}, handlers(results[0]))
+	assert.Empty(t, results[1])
+}
+
+func TestGetN(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"files", "~", "data"}, "static")
+	tree.Add([]string{"files", ":dir", "data"}, "param")
+	tree.Add([]string{"files", "*filepath"}, "wildcard")
+	tree.Add([]string{"files", "*other"}, "wildcard_other")
+
+	path := []string{"files", "~", "data"}
+	assert.Len(t, tree.Get(path), 4)
+	assert.Len(t, tree.GetN(path, 0), 4, "n <= 0 should return all matches")
+	assert.Len(t, tree.GetN(path, -1), 4, "n <= 0 should return all matches")
+	assert.Len(t, tree.GetN(path, 10), 4)
+
+	routes := tree.GetN(path, 1)
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "static", routes[0].Handler.(string))
+
+	routes = tree.GetN(path, 2)
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "static", routes[0].Handler.(string))
+	assert.Equal(t, "param", routes[1].Handler.(string))
+
+	routes = tree.GetN(path, 3)
+	assert.Len(t, routes, 3)
+	assert.Equal(t, "wildcard", routes[2].Handler.(string))
+
+	assert.Len(t, tree.GetN([]string{"unknown"}, 2), 0)
+}
+
+func TestDeepStaticRoute(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	path := make([]string, 50000)
+	for i := range path {
+		path[i] = fmt.Sprintf("s%d", i)
+	}
+
+	_, err := tree.Add(path, "deep")
+	assert.NoError(t, err)
+
+	routes := tree.Get(path)
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "deep", routes[0].Handler.(string))
+
+	assert.Len(t, tree.Get(path[:len(path)-1]), 0)
+}
+
+func TestZeroSegmentWildcard(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"files", "**filepath"}, "files")
+	tree.Add([]string{"static", "*filename"}, "static")
+
+	routes := tree.Get([]string{"files"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "files", routes[0].Handler.(string))
+	assert.Equal(t, radix.Params{{Key: "filepath", Values: []string{}}}, routes[0].Params)
+
+	routes = tree.Get([]string{"files", "a", "b"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{{Key: "filepath", Values: []string{"a", "b"}}}, routes[0].Params)
+
+	assert.Len(t, tree.Get([]string{"static"}), 0, "Single marker should still need a segment")
+
+	tree.Add([]string{"files"}, "files_root")
+	routes = tree.Get([]string{"files"})
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "files_root", routes[0].Handler.(string))
+	assert.Equal(t, "files", routes[1].Handler.(string))
+
+	assert.NoError(t, tree.Delete([]string{"files", "**filepath"}))
+	assert.Len(t, tree.Get([]string{"files", "a"}), 0)
+}
+
+func TestZeroSegmentWildcardCustomMarkers(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{WildcardPrefix: '[', WildcardSuffix: ']'})
+
+	tree.Add([]string{"files", "[[filepath]]"}, "files")
+
+	routes := tree.Get([]string{"files"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{{Key: "filepath", Values: []string{}}}, routes[0].Params)
+}
+
+func TestNodeWrapperChildren(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"users", "*rest"}, "rest")
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"users", "new"}, "user_new")
+	tree.Add([]string{"users", "admins"}, "admins")
+
+	users, _ := tree.Add([]string{"users"}, "users")
+	handler, found := users.Handler()
+	assert.True(t, found)
+	assert.Equal(t, "users", handler.(string))
+
+	var names []string
+	for _, child := range users.Children() {
+		names = append(names, child.PathName())
+		parent, _ := child.Parent()
+		assert.True(t, parent.Equal(users))
+	}
+	assert.Equal(t, []string{"admins", "new", ":id", "*rest"}, names)
+
+	root := tree.Root()
+	_, found = root.Handler()
+	assert.False(t, found, "Root should have no handler")
+	assert.Len(t, root.Children(), 1)
+	assert.Empty(t, root.Children()[0].Children()[0].Children(), "Leaf should have no children")
+}
+
+func TestNodeWrapperPattern(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	nw, _ := tree.Add([]string{"api", "v1", "users", ":id"}, "user")
+	assert.Equal(t, "/api/v1/users/:id", nw.Pattern())
+
+	nw, _ = tree.Add([]string{"files", "**path"}, "files")
+	assert.Equal(t, "/files/**path", nw.Pattern())
+
+	leaf, _, found := tree.GetLeaf([]string{"api", "v1", "users", "42"})
+	assert.True(t, found)
+	assert.Equal(t, "/api/v1/users/:id", leaf.Pattern())
+
+	assert.Equal(t, "/", tree.Root().Pattern())
+
+	braces := radix.NewRadixTreeWithOptions(radix.Options{ParamPrefix: '{', ParamSuffix: '}'})
+	nw, _ = braces.Add([]string{"users", "{id}"}, "user")
+	assert.Equal(t, "/users/{id}", nw.Pattern())
+}
+
+func TestNodeWrapperNodeType(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	wildcard, _ := tree.Add([]string{"users", ":id", "*rest"}, "rest")
+	param, _ := wildcard.Parent()
+	static, _ := param.Parent()
+
+	assert.Equal(t, radix.Wildcard, wildcard.NodeType())
+	assert.Equal(t, "rest", wildcard.ParamName())
+	assert.Equal(t, radix.ParamNode, param.NodeType())
+	assert.Equal(t, "id", param.ParamName())
+	assert.Equal(t, radix.Static, static.NodeType())
+	assert.Empty(t, static.ParamName())
+}
+
+func TestDeleteMissingPathKeepsSize(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"api", "v1", "users", ":id"}, "user_show")
+	tree.Add([]string{"api", "v1", "posts"}, "posts")
+
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, tree.Delete([]string{"api", "v1", "users", ":id", "posts", "x"}), radix.ErrPathNotFound)
+		assert.ErrorIs(t, tree.Delete([]string{"api", "v1", "users"}), radix.ErrPathNotFound, "Intermediate node has no handler")
+		assert.ErrorIs(t, tree.Delete([]string{"api", "v2"}), radix.ErrPathNotFound)
+		assert.ErrorIs(t, tree.Delete([]string{}), radix.ErrPathNotFound)
+	}
+	assert.Equal(t, uint32(2), tree.Size())
+
+	users, _ := tree.Add([]string{"api", "v1", "users"}, "users")
+	assert.Equal(t, uint32(2), users.Size())
+
+	assert.Nil(t, tree.Delete([]string{"api", "v1", "posts"}))
+	assert.ErrorIs(t, tree.Delete([]string{"api", "v1", "posts"}), radix.ErrPathNotFound)
+	assert.Equal(t, uint32(2), tree.Size())
+	assert.Equal(t, uint32(tree.Count()), tree.Size())
+}
+
+func TestDeleteCopiesOnlyItsPath(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"users", ":id", "posts"}, "user_posts")
+	tree.Add([]string{"files", "*filepath"}, "files")
+	before := tree.Clone()
+
+	assert.Nil(t, tree.Delete([]string{"users", ":id", "posts"}))
+	assert.False(t, tree.SameNodeForTest(before, []string{"users", ":id"}))
+	assert.True(t, tree.SameNodeForTest(before, []string{"files", "*filepath"}), "Nodes off the deleted path should be shared")
+	assert.Len(t, before.Get([]string{"users", "1", "posts"}), 1, "Earlier versions should keep the deleted route")
+
+	assert.ErrorIs(t, tree.Delete([]string{"files", "missing"}), radix.ErrPathNotFound)
+	assert.True(t, tree.SameNodeForTest(before, []string{"files"}), "A failed delete should publish nothing")
+}
+
+func TestDeleteIf(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"plugins", ":name"}, "owner_a")
+	tree.Add([]string{"plugins", ":name", "config"}, "config")
+
+	isOwnerB := func(h radix.Handler) bool { return h == "owner_b" }
+	err := tree.DeleteIf([]string{"plugins", ":name"}, isOwnerB)
+	assert.ErrorIs(t, err, radix.ErrHandlerMismatch)
+	assert.Equal(t, []string{"owner_a"}, handlers(tree.Get([]string{"plugins", "search"})), "The route should survive a rejected delete")
+	assert.Equal(t, uint32(2), tree.Size())
+
+	isOwnerA := func(h radix.Handler) bool { return h == "owner_a" }
+	assert.Nil(t, tree.DeleteIf([]string{"plugins", ":name"}, isOwnerA))
+	assert.Empty(t, tree.Get([]string{"plugins", "search"}))
+	assert.Equal(t, uint32(1), tree.Size())
+
+	err = tree.DeleteIf([]string{"plugins", ":name"}, isOwnerA)
+	assert.ErrorIs(t, err, radix.ErrPathNotFound)
+	err = tree.DeleteIf([]string{"missing"}, func(radix.Handler) bool { return true })
+	assert.ErrorIs(t, err, radix.ErrPathNotFound)
+
+	assert.NotNil(t, tree.DeleteIf([]string{"plugins", ":name", "config"}, nil), "A nil predicate should be rejected")
+	assert.Equal(t, uint32(1), tree.Size())
+	assert.Nil(t, tree.Delete([]string{"plugins", ":name", "config"}), "A rejected predicate should not hold the lock")
+}
+
+func TestDeleteSubtree(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"admin"}, "admin")
+	tree.Add([]string{"admin", "users", ":id"}, "admin_user")
+	tree.Add([]string{"admin", "settings"}, "admin_settings")
+	tree.Add([]string{"admin", "*rest"}, "admin_rest")
+	tree.AddMethod("POST", []string{"admin", "settings"}, "admin_settings_post")
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"users", ":id", "posts"}, "user_posts")
+	assert.Equal(t, uint32(7), tree.Size())
+
+	removed, err := tree.DeleteSubtree([]string{"admin"})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, removed)
+	assert.Equal(t, uint32(2), tree.Size())
+	assert.Equal(t, uint32(tree.Count()), tree.Size())
+	assert.Len(t, tree.Get([]string{"admin"}), 0)
+	assert.Len(t, tree.Get([]string{"admin", "anything"}), 0)
+	assert.Len(t, tree.Root().Children(), 1, "Detached branch should be gone")
+
+	removed, err = tree.DeleteSubtree([]string{"users", ":id", "posts"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Len(t, tree.Get([]string{"users", "42"}), 1)
+
+	removed, err = tree.DeleteSubtree([]string{"users", ":id"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Zero(t, tree.Size())
+	assert.Empty(t, tree.Root().Children(), "Emptied ancestors should be pruned")
+}
+
+func TestDeleteSubtreeMissingPrefix(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"admin", "users"}, "admin_users")
+
+	removed, err := tree.DeleteSubtree([]string{"admin", "posts"})
+	assert.ErrorIs(t, err, radix.ErrPathNotFound)
+	assert.Zero(t, removed)
+	assert.Equal(t, uint32(1), tree.Size())
+	assert.Len(t, tree.Get([]string{"admin", "users"}), 1)
+}
+
+func TestDeleteSubtreeRoot(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{}, "root")
+	tree.Add([]string{"a", "b"}, "a_b")
+
+	removed, err := tree.DeleteSubtree([]string{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, removed)
+	assert.Zero(t, tree.Size())
+	assert.Len(t, tree.Get([]string{}), 0)
+}
+
+func TestGetLeaf(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	show, _ := tree.Add([]string{"users", ":id"}, "user_show")
+	files, _ := tree.Add([]string{"files", "*filepath"}, "files")
+	tree.Add([]string{"users", "new"}, "user_new")
+
+	leaf, params, found := tree.GetLeaf([]string{"users", "42"})
+	assert.True(t, found)
+	assert.True(t, leaf.Equal(show))
+	assert.Equal(t, []string{"users", ":id"}, leaf.Path())
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, params)
+
+	leaf, _, found = tree.GetLeaf([]string{"users", "new"})
+	assert.True(t, found)
+	assert.Equal(t, "new", leaf.PathName(), "Static match should win")
+
+	leaf, params, found = tree.GetLeaf([]string{"files", "a", "b"})
+	assert.True(t, found)
+	assert.True(t, leaf.Equal(files))
+	assert.Equal(t, radix.Params{{Key: "filepath", Values: []string{"a", "b"}}}, params)
+
+	leaf, params, found = tree.GetLeaf([]string{"missing"})
+	assert.False(t, found)
+	assert.Nil(t, leaf)
+	assert.Nil(t, params)
+}
+
+func TestCanAdd(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{
+		StrictWildcards: true,
+		StrictNames:     true,
+		StrictChildren:  true,
+		UniqueParams:    true,
+	})
+	tree.Add([]string{"users", ":id"}, "user")
+	tree.Add([]string{"files", "*path"}, "files")
+	tree.Add([]string{"about"}, "about")
+	before := tree.Stats()
+
+	for _, path := range [][]string{
+		{"users", ":id"},
+		{"users", ":id", "posts"},
+		{"users", "me"},
+		{"files", "*path", "meta"},
+		{"files", "*other"},
+		{"files", ":name"},
+		{"orders", ":"},
+		{"orders", ":id", ":id"},
+		{"about"},
+		{"contact"},
+		{},
+	} {
+		err := tree.CanAdd(path)
+		_, addErr := tree.Clone().Add(path, "handler")
+		assert.Equal(t, addErr, err, "CanAdd should mirror Add for %v", path)
+	}
+
+	assert.ErrorIs(t, tree.CanAdd([]string{"users", ":id"}), radix.ErrHandlerExists)
+	assert.ErrorIs(t, tree.CanAdd([]string{"files", "*path", "meta"}), radix.ErrWildcardNotLast)
+	assert.Nil(t, tree.CanAdd([]string{"contact", ":name"}))
+
+	assert.Equal(t, before, tree.Stats(), "CanAdd should not change the tree")
+	assert.Equal(t, uint32(3), tree.Size())
+	assert.Empty(t, tree.Get([]string{"contact", "x"}))
+}
+
+func TestCanAddKeepsStaticFastPath(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"about"}, "about")
+
+	assert.Nil(t, tree.CanAdd([]string{"users", ":id"}))
+	nw, _ := tree.Add([]string{"contact"}, "contact")
+	assert.Equal(t, uint64(2), nw.Sequence(), "CanAdd should not use up a sequence number")
+	assert.Equal(t, []string{"about"}, handlers(tree.Get([]string{"about"})))
+}
+
+func TestSetRoot(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users"}, "users")
+
+	assert.Nil(t, tree.SetRoot("root"))
+	assert.Equal(t, uint32(2), tree.Size())
+
+	routes := tree.Get([]string{})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "root", routes[0].Handler.(string))
+
+	assert.ErrorIs(t, tree.SetRoot("again"), radix.ErrHandlerExists)
+	_, err := tree.Add([]string{}, "again")
+	assert.ErrorIs(t, err, radix.ErrHandlerExists, "Add should see the root handler too")
+
+	assert.Nil(t, tree.Delete([]string{}))
+	assert.Nil(t, tree.SetRoot("root"), "Root should be settable again after Delete")
+}
+
+func TestGetContext(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"users", "*rest"}, "user_rest")
+
+	routes, err := tree.GetContext(context.Background(), []string{"users", "42"})
+	assert.Nil(t, err)
+	assert.Equal(t, tree.Get([]string{"users", "42"}), routes)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	routes, err = tree.GetContext(ctx, []string{"users", "42"})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, routes)
+}
+
+// countdownContext reports cancellation once Err has been called n times.
+type countdownContext struct {
+	context.Context
+	n int
+}
+
+func (c *countdownContext) Err() error {
+	if c.n--; c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestGetContextCancelledMidway(t *testing.T) {
+	tree := radix.NewRadixTree()
+	for _, a := range []string{":a", ":b"} {
+		for _, b := range []string{":c", ":d"} {
+			tree.Add([]string{a, b, "*rest"}, a+b)
+		}
+	}
+	path := []string{"x", "y", "z"}
+	assert.Len(t, tree.Get(path), 4)
+
+	ctx := &countdownContext{Context: context.Background(), n: 3}
+	routes, err := tree.GetContext(ctx, path)
+	assert.ErrorIs(t, err, context.Canceled, "Cancellation during traversal should be reported")
+	assert.Nil(t, routes)
+}
+
+func TestStaticOnlyLookup(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{}, "root")
+	tree.Add([]string{"api", "users"}, "users")
+	tree.AddMethod("GET", []string{"api", "users"}, "users_get")
+
+	routes := tree.Get([]string{"api", "users"})
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "users", routes[0].Handler.(string))
+	assert.Equal(t, "GET", routes[1].Method)
+	assert.Len(t, tree.Get([]string{}), 1)
+	assert.Len(t, tree.Get([]string{"api"}), 0)
+	assert.Len(t, tree.Get([]string{"api", "users", "42"}), 0)
+
+	tree.Add([]string{"api", ":resource", ":id"}, "resource")
+	routes = tree.Get([]string{"api", "users", "42"})
+	assert.Len(t, routes, 1, "Lookups should see dynamic routes once they exist")
+	assert.Equal(t, "resource", routes[0].Handler.(string))
+
+	tree.Delete([]string{"api", ":resource", ":id"})
+	assert.Len(t, tree.Get([]string{"api", "users"}), 2)
+	assert.Len(t, tree.Clone().Get([]string{"api", "users"}), 2)
+}
+
+func TestTopLevelSegments(t *testing.T) {
+	tree := radix.NewRadixTree()
+	assert.Equal(t, []string{}, tree.TopLevelSegments())
+
+	tree.Add([]string{}, "root")
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"api", "v1"}, "api_v1")
+	tree.Add([]string{":lang"}, "lang")
+	tree.Add([]string{"*rest"}, "rest")
+	tree.Add([]string{`\:443`}, "port")
+
+	assert.Equal(t, []string{`\:443`, "api", "users", ":lang", "*rest"}, tree.TopLevelSegments())
+}
+
+func TestDeleteRootHandler(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{}, "root")
+	tree.Add([]string{"users"}, "users")
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"files", "*filepath"}, "files")
+	assert.Equal(t, uint32(4), tree.Size())
+
+	assert.Nil(t, tree.Delete([]string{}))
+	assert.Equal(t, uint32(3), tree.Size(), "Deleting the root should remove exactly one route")
+	assert.Equal(t, uint32(tree.Count()), tree.Size())
+	assert.Len(t, tree.Get([]string{}), 0)
+	assert.Len(t, tree.Get([]string{"users"}), 1, "Child routes should remain reachable")
+	assert.Len(t, tree.Get([]string{"users", "42"}), 1)
+	assert.Len(t, tree.Get([]string{"files", "a"}), 1)
+
+	assert.ErrorIs(t, tree.Delete([]string{}), radix.ErrPathNotFound)
+	assert.Equal(t, uint32(3), tree.Size())
+
+	assert.Nil(t, tree.SetRoot("root"))
+	assert.Equal(t, uint32(4), tree.Size())
+	assert.Nil(t, tree.Delete([]string{"users"}))
+	assert.Nil(t, tree.Delete([]string{"users", ":id"}))
+	assert.Nil(t, tree.Delete([]string{"files", "*filepath"}))
+	assert.Equal(t, uint32(1), tree.Size())
+	assert.Nil(t, tree.Delete([]string{}))
+	assert.Zero(t, tree.Size())
+	assert.Empty(t, tree.Root().Children())
+}
+
+func TestDeleteRootKeepsMethods(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{}, "root")
+	tree.AddMethod("GET", []string{}, "root_get")
+	tree.Add([]string{"users"}, "users")
+
+	assert.Nil(t, tree.Delete([]string{}))
+	assert.Equal(t, uint32(2), tree.Size())
+	routes := tree.Get([]string{})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "GET", routes[0].Method)
+}
@@ -0,0 +1,96 @@
+package radix
+
+import (
+	"fmt"
+	"slices"
+)
+
+// RenameStatic changes the label of the static segment at path to newName.
+// The node keeps its handler and descendants, so every route below it moves
+// to the new prefix.
+func (r *RadixTree) RenameStatic(path []string, newName string) error {
+	nodeType, literal := r.classify(newName)
+	if literal == "" || nodeType != Static {
+		return fmt.Errorf("invalid static segment name %q", newName)
+	}
+	return r.update(func(root *Node) error {
+		trail := r.ownTrail(root, path)
+		if len(trail) < 2 {
+			return fmt.Errorf("%w: %v", ErrPathNotFound, path)
+		}
+		node, parent := trail[len(trail)-1], trail[len(trail)-2]
+		if node.nodeType != Static {
+			return fmt.Errorf("segment %q is not static", node.path)
+		}
+		r.record(journalEntry{Op: journalRenameStatic, Path: path, NewName: newName})
+		if node.path == literal {
+			return nil
+		}
+		if _, exists := parent.static_children[literal]; exists {
+			return fmt.Errorf("%w: %q", ErrNodeExists, literal)
+		}
+
+		delete(parent.static_children, node.path)
+		if i, found := slices.BinarySearch(parent.static_keys, node.path); found {
+			parent.static_keys = slices.Delete(parent.static_keys, i, i+1)
+		}
+		node.path = literal
+		parent.static_children[literal] = node
+		i, _ := slices.BinarySearch(parent.static_keys, literal)
+		parent.static_keys = slices.Insert(parent.static_keys, i, literal)
+		return nil
+	})
+}
+
+// RenameParam changes the name of the param segment at path to newName,
+// given with or without its marker. Routes through the node keep their
+// handlers and descendants and capture the param under the new key.
+func (r *RadixTree) RenameParam(path []string, newName string) error {
+	if nodeType, name := r.classify(newName); nodeType == ParamNode {
+		newName = name
+	}
+	if nodeType, _ := r.classify(newName); newName == "" || nodeType != Static {
+		return fmt.Errorf("invalid param name %q", newName)
+	}
+	return r.update(func(root *Node) error {
+		trail := r.ownTrail(root, path)
+		if len(trail) < 2 {
+			return fmt.Errorf("%w: %v", ErrPathNotFound, path)
+		}
+		node, parent := trail[len(trail)-1], trail[len(trail)-2]
+		if node.nodeType != ParamNode {
+			return fmt.Errorf("segment %q is not a param", node.path)
+		}
+		r.record(journalEntry{Op: journalRenameParam, Path: path, NewName: newName})
+		if node.paramName == newName {
+			return nil
+		}
+		if _, exists := parent.params_children[newName]; exists {
+			return fmt.Errorf("%w: %q", ErrNodeExists, newName)
+		}
+		if r.opts.UniqueParams && (capturesParam(trail[:len(trail)-1], newName) || capturedBelow(node, newName)) {
+			return fmt.Errorf("%w: %q in %v", ErrDuplicateParam, newName, path)
+		}
+
+		delete(parent.params_children, node.paramName)
+		if i, found := slices.BinarySearch(parent.params_keys, node.paramName); found {
+			parent.params_keys = slices.Delete(parent.params_keys, i, i+1)
+		}
+		node.paramName = newName
+		node.path = r.paramSegment(newName)
+		parent.params_children[newName] = node
+		i, _ := slices.BinarySearch(parent.params_keys, newName)
+		parent.params_keys = slices.Insert(parent.params_keys, i, newName)
+		return nil
+	})
+}
+
+// capturedBelow reports whether a param or wildcard below n captures name.
+func capturedBelow(n *Node, name string) bool {
+	for _, child := range n.sortedChildren() {
+		if capturesParam([]*Node{child}, name) || capturedBelow(child, name) {
+			return true
+		}
+	}
+	return false
+}
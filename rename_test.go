@@ -0,0 +1,149 @@
+package radix_test
+
+import (
+	"errors"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenameStatic(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"api", "v1"}, "api_v1")
+	tree.Add([]string{"api", "v1", "users", ":id"}, "user_show")
+	tree.Add([]string{"api", "v2"}, "api_v2")
+
+	err := tree.RenameStatic([]string{"api", "v1"}, "version1")
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), tree.Size(), "Renaming should not change the route count")
+
+	routes := tree.Get([]string{"api", "version1", "users", "42"})
+	assert.Len(t, routes, 1, "Descendants should move with the renamed node")
+	assert.Equal(t, "user_show", routes[0].Handler.(string))
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, routes[0].Params)
+
+	routes = tree.Get([]string{"api", "version1"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "api_v1", routes[0].Handler.(string))
+
+	assert.Len(t, tree.Get([]string{"api", "v1"}), 0, "Old path should no longer resolve")
+	assert.Len(t, tree.Get([]string{"api", "v1", "users", "42"}), 0, "Old path should no longer resolve")
+}
+
+func TestRenameStaticErrors(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"api", "v1"}, "api_v1")
+	tree.Add([]string{"api", "v2"}, "api_v2")
+	tree.Add([]string{"users", ":id"}, "user_show")
+
+	err := tree.RenameStatic([]string{"api", "v1"}, "v2")
+	assert.True(t, errors.Is(err, radix.ErrNodeExists), "Renaming onto an existing sibling should fail")
+
+	err = tree.RenameStatic([]string{"api", "v3"}, "v4")
+	assert.True(t, errors.Is(err, radix.ErrPathNotFound), "Renaming a missing node should fail")
+
+	err = tree.RenameStatic([]string{"users", ":id"}, "user_id")
+	assert.NotNil(t, err, "Renaming a param node should fail")
+
+	err = tree.RenameStatic([]string{"api", "v1"}, ":v1")
+	assert.NotNil(t, err, "New name must be a static segment")
+
+	routes := tree.Get([]string{"api", "v1"})
+	assert.Len(t, routes, 1, "Failed renames should leave the tree unchanged")
+	assert.Equal(t, "api_v1", routes[0].Handler.(string))
+}
+
+func TestRenameParam(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"users", ":id", "posts", ":post"}, "user_post")
+
+	assert.Nil(t, tree.RenameParam([]string{"users", ":id"}, "user_id"))
+	assert.Equal(t, uint32(2), tree.Size())
+
+	routes := tree.Get([]string{"users", "42", "posts", "7"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, radix.Params{
+		{Key: "user_id", Values: []string{"42"}},
+		{Key: "post", Values: []string{"7"}},
+	}, routes[0].Params)
+
+	assert.Nil(t, tree.RenameParam([]string{"users", ":user_id", "posts", ":post"}, ":post_id"), "Marker should be accepted")
+	routes = tree.Get([]string{"users", "42", "posts", "7"})
+	assert.Equal(t, "post_id", routes[0].Params[1].Key)
+
+	assert.ErrorIs(t, tree.Delete([]string{"users", ":id"}), radix.ErrPathNotFound, "Old name should be gone")
+	assert.Nil(t, tree.Delete([]string{"users", ":user_id"}))
+}
+
+func TestRenameParamErrors(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"users", ":name", "profile"}, "user_profile")
+	tree.Add([]string{"files", "*filepath"}, "files")
+
+	err := tree.RenameParam([]string{"users", ":id"}, "name")
+	assert.ErrorIs(t, err, radix.ErrNodeExists, "Sibling param names should not collide")
+
+	assert.NotNil(t, tree.RenameParam([]string{"users"}, "x"), "Static nodes cannot be renamed as params")
+	assert.NotNil(t, tree.RenameParam([]string{"files", "*filepath"}, "x"), "Wildcards cannot be renamed as params")
+	assert.ErrorIs(t, tree.RenameParam([]string{"users", ":missing"}, "x"), radix.ErrPathNotFound)
+	assert.NotNil(t, tree.RenameParam([]string{"users", ":id"}, ""))
+	assert.NotNil(t, tree.RenameParam([]string{"users", ":id"}, "*x"))
+
+	routes := tree.Get([]string{"users", "42"})
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, routes[0].Params, "Failed renames should leave the tree unchanged")
+}
+
+func TestRenameParamUniqueParams(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{UniqueParams: true})
+	tree.Add([]string{"a", ":x", ":y"}, "a")
+	tree.Add([]string{"b", ":y", "c", "*x"}, "b")
+
+	err := tree.RenameParam([]string{"a", ":x", ":y"}, "x")
+	assert.ErrorIs(t, err, radix.ErrDuplicateParam, "An ancestor should already capture the name")
+	err = tree.RenameParam([]string{"a", ":x"}, "y")
+	assert.ErrorIs(t, err, radix.ErrDuplicateParam, "A descendant should already capture the name")
+	err = tree.RenameParam([]string{"b", ":y"}, "x")
+	assert.ErrorIs(t, err, radix.ErrDuplicateParam, "Wildcards share the param namespace")
+
+	routes := tree.Get([]string{"a", "1", "2"})
+	assert.Equal(t, radix.Params{{Key: "x", Values: []string{"1"}}, {Key: "y", Values: []string{"2"}}}, routes[0].Params,
+		"Failed renames should leave the tree unchanged")
+	assert.Nil(t, tree.RenameParam([]string{"a", ":x", ":y"}, "z"))
+}
+
+func TestRenameParamCustomMarkers(t *testing.T) {
+	tree := radix.NewRadixTreeWithOptions(radix.Options{ParamPrefix: '{', ParamSuffix: '}'})
+	tree.Add([]string{"users", "{id}"}, "user_show")
+
+	assert.Nil(t, tree.RenameParam([]string{"users", "{id}"}, "{user_id}"))
+	assert.Nil(t, tree.Delete([]string{"users", "{user_id}"}))
+}
+
+func TestRenameParamKeepsKeyOrder(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"users", ":b"}, "b")
+	tree.Add([]string{"users", ":c"}, "c")
+	tree.Add([]string{"users", ":a"}, "a")
+	handlers := func() []string {
+		var names []string
+		for _, route := range tree.Get([]string{"users", "42"}) {
+			names = append(names, route.Handler.(string))
+		}
+		return names
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, handlers(), "Param children should be tried in key order")
+
+	assert.Nil(t, tree.RenameParam([]string{"users", ":a"}, "d"))
+	assert.Equal(t, []string{"b", "c", "a"}, handlers(), "A renamed param should move to its new key")
+
+	assert.Nil(t, tree.Delete([]string{"users", ":c"}))
+	assert.Equal(t, []string{"b", "a"}, handlers())
+}
@@ -0,0 +1,18 @@
+package radix
+
+// SetNotFound sets the handler Resolve returns when no route matches, which
+// the Handler adapter also falls back to. Like SetMetrics, call it before
+// the tree is shared.
+func (r *RadixTree) SetNotFound(h Handler) {
+	r.notFound = h
+}
+
+// Resolve returns the highest-priority route matching path or, when there is
+// none, a route holding the NotFound handler with empty Params. The Handler
+// of that route is nil if SetNotFound was never called.
+func (r *RadixTree) Resolve(path []string) Route {
+	if routes := r.GetN(path, 1); len(routes) > 0 {
+		return routes[0]
+	}
+	return Route{Handler: r.notFound, Params: Params{}}
+}
@@ -0,0 +1,30 @@
+package radix_test
+
+import (
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"users", "*rest"}, "user_rest")
+
+	route := tree.Resolve([]string{"users", "42"})
+	assert.Equal(t, "user_show", route.Handler.(string), "Best match should win")
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, route.Params)
+
+	route = tree.Resolve([]string{"posts"})
+	assert.Nil(t, route.Handler, "Without a NotFound handler the route is empty")
+
+	tree.SetNotFound("not_found")
+	route = tree.Resolve([]string{"posts"})
+	assert.Equal(t, "not_found", route.Handler.(string))
+	assert.Empty(t, route.Params)
+	assert.NotNil(t, route.Params)
+
+	assert.Len(t, tree.Get([]string{"posts"}), 0, "Get should not use the NotFound handler")
+	assert.Equal(t, "not_found", tree.Clone().Resolve([]string{"posts"}).Handler.(string))
+}
@@ -0,0 +1,65 @@
+package radix
+
+import "fmt"
+
+// ParamNames returns the names of the params and wildcards declared along
+// the registered route path, in order from the root, such as
+// ["id", "post_id"] for "/users/:id/posts/:post_id". It returns
+// ErrPathNotFound if no route is registered at path.
+func (r *RadixTree) ParamNames(path []string) ([]string, error) {
+	trail := r.findTrail(r.root.Load(), path)
+	if trail == nil {
+		return nil, fmt.Errorf("%w: %v", ErrPathNotFound, path)
+	}
+	if node := trail[len(trail)-1]; node.handler == nil && len(node.methods) == 0 {
+		return nil, fmt.Errorf("%w: %v", ErrPathNotFound, path)
+	}
+	names := []string{}
+	for _, n := range trail[1:] {
+		if n.nodeType != Static {
+			names = append(names, n.paramName)
+		}
+	}
+	return names, nil
+}
+
+// BuildPath generates a concrete path for the registered route pattern,
+// replacing each param segment with its value in params. A wildcard's value
+// is split with ParsePath, so {"path": "css/site.css"} expands "*path" into
+// two segments. It returns ErrPathNotFound if no route is registered at
+// pattern, ErrMissingParam if params lacks a name, and an error if a value
+// is rejected by the param's constraint or gives a wildcard too few
+// segments.
+func (r *RadixTree) BuildPath(pattern []string, params map[string]string) ([]string, error) {
+	trail := r.findTrail(r.root.Load(), pattern)
+	if trail == nil {
+		return nil, fmt.Errorf("%w: %v", ErrPathNotFound, pattern)
+	}
+	if node := trail[len(trail)-1]; node.handler == nil && len(node.methods) == 0 {
+		return nil, fmt.Errorf("%w: %v", ErrPathNotFound, pattern)
+	}
+	path := make([]string, 0, len(pattern))
+	for _, n := range trail[1:] {
+		if n.nodeType == Static {
+			path = append(path, n.path)
+			continue
+		}
+		value, ok := params[n.paramName]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrMissingParam, n.paramName)
+		}
+		if n.nodeType == ParamNode {
+			if n.constraint != nil && !n.constraint.MatchString(value) {
+				return nil, fmt.Errorf("value %q for %q does not match %s", value, n.paramName, n.constraint)
+			}
+			path = append(path, value)
+			continue
+		}
+		segments := ParsePath(value)
+		if len(segments) < n.minCapture() {
+			return nil, fmt.Errorf("value %q for %q has fewer than %d segments", value, n.paramName, n.minCapture())
+		}
+		path = append(path, segments...)
+	}
+	return path, nil
+}
@@ -0,0 +1,89 @@
+package radix_test
+
+import (
+	"regexp"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamNames(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id", "posts", ":post_id"}, "post")
+	tree.Add([]string{"files", ":bucket", "*path"}, "file")
+	tree.Add([]string{"about"}, "about")
+	tree.AddMethod("GET", []string{"orders", ":order"}, "order")
+
+	names, err := tree.ParamNames([]string{"users", ":id", "posts", ":post_id"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"id", "post_id"}, names)
+
+	names, err = tree.ParamNames([]string{"files", ":bucket", "*path"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"bucket", "path"}, names)
+
+	names, err = tree.ParamNames([]string{"about"})
+	assert.Nil(t, err)
+	assert.Empty(t, names)
+
+	names, err = tree.ParamNames([]string{"orders", ":order"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"order"}, names)
+}
+
+func TestParamNamesNotRegistered(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id", "posts", ":post_id"}, "post")
+
+	_, err := tree.ParamNames([]string{"users", ":id"})
+	assert.ErrorIs(t, err, radix.ErrPathNotFound, "Intermediate nodes are not routes")
+
+	_, err = tree.ParamNames([]string{"users", "42", "posts", "7"})
+	assert.ErrorIs(t, err, radix.ErrPathNotFound, "Paths are matched as patterns, not looked up")
+}
+
+func TestBuildPath(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id", "posts", ":post_id"}, "post")
+	tree.Add([]string{"static", ":bucket", "*path"}, "file")
+	tree.Add([]string{"docs", "**page"}, "docs")
+	tree.Add([]string{"ports", `\:443`}, "port")
+
+	path, err := tree.BuildPath([]string{"users", ":id", "posts", ":post_id"}, map[string]string{"id": "42", "post_id": "7"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"users", "42", "posts", "7"}, path)
+	assert.Len(t, tree.Get(path), 1)
+
+	path, err = tree.BuildPath([]string{"static", ":bucket", "*path"}, map[string]string{"bucket": "assets", "path": "css/site.css"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"static", "assets", "css", "site.css"}, path)
+
+	path, err = tree.BuildPath([]string{"docs", "**page"}, map[string]string{"page": ""})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"docs"}, path)
+
+	path, err = tree.BuildPath([]string{"ports", `\:443`}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ports", ":443"}, path)
+}
+
+func TestBuildPathErrors(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id", "posts", ":post_id"}, "post")
+	tree.Add([]string{"static", "*path"}, "file")
+	tree.AddWithConstraints([]string{"orders", ":id"}, "order", map[string]*regexp.Regexp{"id": regexp.MustCompile(`^\d+$`)})
+
+	_, err := tree.BuildPath([]string{"users", ":id", "posts", ":post_id"}, map[string]string{"id": "42"})
+	assert.ErrorIs(t, err, radix.ErrMissingParam)
+	assert.ErrorContains(t, err, `"post_id"`)
+
+	_, err = tree.BuildPath([]string{"users", ":id"}, map[string]string{"id": "42"})
+	assert.ErrorIs(t, err, radix.ErrPathNotFound)
+
+	_, err = tree.BuildPath([]string{"orders", ":id"}, map[string]string{"id": "abc"})
+	assert.Error(t, err, "Values must satisfy the param's constraint")
+
+	_, err = tree.BuildPath([]string{"static", "*path"}, map[string]string{"path": ""})
+	assert.Error(t, err, "A wildcard needs at least one segment")
+}
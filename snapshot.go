@@ -0,0 +1,46 @@
+package radix
+
+// Snapshot is a read-only view of a RadixTree frozen when it was taken.
+// Writes to the tree afterwards publish new versions and leave the snapshot
+// untouched, so a series of lookups through it sees one consistent set of
+// routes. It is safe for concurrent use, and holding it only keeps its
+// version of the tree from being garbage collected.
+type Snapshot struct {
+	tree    *RadixTree
+	root    *Node
+	dynamic bool
+}
+
+// Snapshot returns a view of the tree as it is now. Taking one copies
+// nothing.
+func (r *RadixTree) Snapshot() *Snapshot {
+	// As in Get, the root is loaded before dynamic is checked.
+	root := r.root.Load()
+	return &Snapshot{tree: r, root: root, dynamic: r.dynamic.Load()}
+}
+
+// Get looks up path in the snapshot like RadixTree.Get.
+func (s *Snapshot) Get(path []string) Routes {
+	var routes Routes
+	if s.dynamic {
+		var buf trailBuffer
+		routes = s.tree.getValue(buf.start(s.root), path, nil, lookup{})
+	} else {
+		routes = s.tree.getStatic(s.root, path)
+	}
+	if s.tree.metrics != nil {
+		s.tree.observe(path, routes)
+	}
+	return routes
+}
+
+// GetMethod looks up path in the snapshot like RadixTree.GetMethod.
+func (s *Snapshot) GetMethod(method string, path []string) Routes {
+	var buf trailBuffer
+	return s.tree.getValue(buf.start(s.root), path, nil, lookup{method: method})
+}
+
+// Size returns the number of routes in the snapshot.
+func (s *Snapshot) Size() uint32 {
+	return s.root.nodeSize
+}
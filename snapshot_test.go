@@ -0,0 +1,73 @@
+package radix_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users", ":id"}, "user")
+	tree.AddMethod("GET", []string{"orders"}, "orders")
+
+	snapshot := tree.Snapshot()
+
+	tree.Delete([]string{"users", ":id"})
+	tree.Add([]string{"users", "me"}, "me")
+	tree.Add([]string{"posts"}, "posts")
+
+	routes := snapshot.Get([]string{"users", "me"})
+	assert.Equal(t, []string{"user"}, handlers(routes), "Writes after Snapshot should not show")
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"me"}}}, routes[0].Params)
+	assert.Nil(t, snapshot.Get([]string{"posts"}))
+	assert.Equal(t, []string{"orders"}, handlers(snapshot.GetMethod("GET", []string{"orders"})))
+	assert.Equal(t, uint32(2), snapshot.Size())
+
+	assert.Equal(t, []string{"me"}, handlers(tree.Get([]string{"users", "me"})))
+	assert.Equal(t, uint32(3), tree.Size())
+}
+
+func TestSnapshotStaticTree(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"about"}, "about")
+
+	snapshot := tree.Snapshot()
+	tree.Add([]string{":page"}, "page")
+
+	assert.Equal(t, []string{"about"}, handlers(snapshot.Get([]string{"about"})))
+	assert.Nil(t, snapshot.Get([]string{"contact"}), "A static snapshot should not see later params")
+	assert.Equal(t, []string{"page"}, handlers(tree.Get([]string{"contact"})))
+}
+
+// TestSnapshotConsistentUnderWrites takes snapshots while a writer adds
+// routes; run it with -race. Every snapshot must hold exactly the routes
+// its Size reports.
+func TestSnapshotConsistentUnderWrites(t *testing.T) {
+	tree := radix.NewRadixTree()
+	const count = 200
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range count {
+			tree.Add([]string{"r", fmt.Sprint(i), ":id"}, i)
+		}
+	}()
+
+	for range 50 {
+		snapshot := tree.Snapshot()
+		size := int(snapshot.Size())
+		for i := range count {
+			found := snapshot.Get([]string{"r", fmt.Sprint(i), "x"}) != nil
+			if found != (i < size) {
+				t.Fatalf("Snapshot of size %d reports route %d found=%v", size, i, found)
+			}
+		}
+	}
+	wg.Wait()
+}
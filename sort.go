@@ -0,0 +1,62 @@
+package radix
+
+import (
+	"slices"
+	"strings"
+)
+
+// Sort orders rs by specificity, as documented on Routes. Routes that were
+// not produced by a lookup sort last.
+func (rs Routes) Sort() {
+	slices.SortStableFunc(rs, compareRoutes)
+}
+
+func compareRoutes(a, b Route) int {
+	if a.trail == nil || b.trail == nil {
+		return compareBool(a.trail == nil, b.trail == nil)
+	}
+	if a.leaf() != b.leaf() {
+		ta, tb := a.trail, b.trail
+		for i := 1; i < len(ta) && i < len(tb); i++ {
+			if c := compareNodes(ta[i-1], tb[i-1], ta[i], tb[i]); c != 0 {
+				return c
+			}
+		}
+		if c := len(ta) - len(tb); c != 0 {
+			return c
+		}
+	}
+	if a.Method == "" || b.Method == "" {
+		return compareBool(a.Method != "", b.Method != "")
+	}
+	return strings.Compare(a.Method, b.Method)
+}
+
+// compareNodes orders two siblings by match priority, given their parents.
+func compareNodes(parentA, parentB, a, b *Node) int {
+	if a == b {
+		return 0
+	}
+	if a.nodeType != b.nodeType {
+		return int(a.nodeType) - int(b.nodeType)
+	}
+	switch a.nodeType {
+	case ParamNode:
+		return strings.Compare(a.paramName, b.paramName)
+	case Wildcard:
+		if parentA == parentB {
+			return slices.Index(parentA.wildcard_children, a) - slices.Index(parentB.wildcard_children, b)
+		}
+	}
+	return strings.Compare(a.path, b.path)
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
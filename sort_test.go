@@ -0,0 +1,56 @@
+package radix_test
+
+import (
+	"math/rand"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func handlers(routes radix.Routes) []string {
+	names := make([]string, len(routes))
+	for i, route := range routes {
+		names[i] = route.Handler.(string)
+	}
+	return names
+}
+
+func TestGetOrderIsStable(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"files", "*filepath"}, "wildcard")
+	tree.Add([]string{"files", ":zeta"}, "zeta")
+	tree.Add([]string{"files", ":alpha"}, "alpha")
+	tree.Add([]string{"files", ":mid"}, "mid")
+	tree.Add([]string{"files", "*other"}, "wildcard_other")
+	tree.Add([]string{"files", "readme"}, "static")
+
+	expected := []string{"static", "alpha", "mid", "zeta", "wildcard", "wildcard_other"}
+	for i := 0; i < 50; i++ {
+		assert.Equal(t, expected, handlers(tree.Get([]string{"files", "readme"})))
+	}
+}
+
+func TestRoutesSort(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"api", "v1", "users"}, "static")
+	tree.Add([]string{"api", ":version", "users"}, "version")
+	tree.Add([]string{"api", ":app", ":kind"}, "app_kind")
+	tree.Add([]string{"api", ":app", "users"}, "app_users")
+	tree.Add([]string{"api", "*rest"}, "rest")
+	tree.AddMethod("GET", []string{"api", "v1", "users"}, "static_get")
+
+	routes := tree.Get([]string{"api", "v1", "users"})
+	expected := []string{"static", "static_get", "app_users", "app_kind", "version", "rest"}
+	assert.Equal(t, expected, handlers(routes))
+
+	shuffled := append(radix.Routes{}, routes...)
+	rand.New(rand.NewSource(1)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	shuffled = append(shuffled, radix.Route{Handler: "detached"})
+	shuffled.Sort()
+	assert.Equal(t, append(expected, "detached"), handlers(shuffled))
+}
@@ -0,0 +1,54 @@
+package radix
+
+// TreeStats describes the shape of a tree.
+type TreeStats struct {
+	// MaxDepth is the number of segments on the longest path from the root.
+	MaxDepth int
+	// TotalNodes counts every node, the root included.
+	TotalNodes int
+	// LeafRoutes counts the nodes holding at least one handler.
+	LeafRoutes int
+	// ParamNodes, WildcardNodes and StaticNodes count the nodes of each
+	// type below the root.
+	ParamNodes    int
+	WildcardNodes int
+	StaticNodes   int
+	// LatestSequence is the largest Sequence of a node holding a handler,
+	// or 0 for an empty tree.
+	LatestSequence uint64
+}
+
+// Stats computes TreeStats in a single traversal.
+func (r *RadixTree) Stats() TreeStats {
+	var stats TreeStats
+	var visit func(node *Node, depth int)
+	visit = func(node *Node, depth int) {
+		stats.TotalNodes++
+		stats.MaxDepth = max(stats.MaxDepth, depth)
+		if node.handler != nil || len(node.methods) > 0 {
+			stats.LeafRoutes++
+			stats.LatestSequence = max(stats.LatestSequence, node.seq)
+		}
+		if depth > 0 {
+			switch node.nodeType {
+			case ParamNode:
+				stats.ParamNodes++
+			case Wildcard:
+				stats.WildcardNodes++
+			default:
+				stats.StaticNodes++
+			}
+		}
+		for _, child := range node.static_children {
+			visit(child, depth+1)
+		}
+		for _, child := range node.params_children {
+			visit(child, depth+1)
+		}
+		for _, child := range node.wildcard_children {
+			visit(child, depth+1)
+		}
+	}
+	visit(r.root.Load(), 0)
+	return stats
+}
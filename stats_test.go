@@ -0,0 +1,27 @@
+package radix_test
+
+import (
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	tree := radix.NewRadixTree()
+	assert.Equal(t, radix.TreeStats{TotalNodes: 1}, tree.Stats())
+
+	for _, route := range mixedRoutes {
+		tree.Add(route.path, route.handler)
+	}
+
+	assert.Equal(t, radix.TreeStats{
+		MaxDepth:       6,
+		TotalNodes:     28,
+		LeafRoutes:     23,
+		ParamNodes:     6,
+		WildcardNodes:  5,
+		StaticNodes:    16,
+		LatestSequence: 23,
+	}, tree.Stats())
+}
@@ -0,0 +1,300 @@
+package radix
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ReplaceSubtree atomically swaps the routes registered at or below prefix
+// for the routes of replacement: a route at path in replacement becomes a
+// route at prefix followed by path, and the route replacement holds at its
+// root becomes the one at prefix. The node at prefix must exist, and both
+// trees must use the same segment markers. Like Mount, the replacement is
+// checked against r's options and normalizer as Add would check its routes.
+// Later changes to replacement do not affect r.
+func (r *RadixTree) ReplaceSubtree(prefix []string, replacement *RadixTree) error {
+	if !r.sameSyntax(replacement) {
+		return fmt.Errorf("replacement tree uses different segment markers")
+	}
+	return r.update(func(root *Node) error {
+		trail := r.ownTrail(root, prefix)
+		if trail == nil {
+			return fmt.Errorf("%w: %q", ErrPathNotFound, "/"+strings.Join(prefix, "/"))
+		}
+		node := trail[len(trail)-1]
+		oldSize := node.nodeSize
+
+		// The node keeps the segment it was registered under and takes
+		// everything else, routes and children, from the replacement,
+		// grafted as Mount grafts so the tree's options are checked.
+		node.handler, node.methods, node.meta, node.isFallthrough = nil, nil, nil, false
+		node.static_children, node.static_keys = nil, nil
+		node.params_children, node.params_keys = nil, nil
+		node.wildcard_children, node.optionalChild = nil, nil
+		node.nodeSize, node.seq = 0, 0
+		replaced := cloneNode(replacement.root.Load(), r.version)
+		r.stampAll(replaced)
+		if _, err := r.graft(trail, replaced, nil); err != nil {
+			return err
+		}
+
+		for _, parent := range trail[:len(trail)-1] {
+			parent.nodeSize = parent.nodeSize - oldSize + node.nodeSize
+		}
+		if len(trail) > 1 {
+			parent := trail[len(trail)-2]
+			if parent.optionalChild == node && node.handler == nil {
+				parent.optionalChild = nil
+			}
+			if node.nodeSize == 0 {
+				parent.removeChild(node)
+				for i := len(trail) - 2; i > 0 && trail[i].nodeSize == 0; i-- {
+					trail[i-1].removeChild(trail[i])
+				}
+			}
+		}
+
+		r.record(journalEntry{Op: journalPrune, Path: prefix})
+		if node.nodeSize > 0 {
+			r.recordRoutes(trail, slices.Clone(prefix))
+		}
+		return nil
+	})
+}
+
+// stampAll stamps the routes at and below node as registered now, in the
+// order WalkSorted visits them.
+func (r *RadixTree) stampAll(node *Node) {
+	if node.handler != nil || len(node.methods) > 0 {
+		r.stamp(node)
+	}
+	for _, child := range node.sortedChildren() {
+		r.stampAll(child)
+	}
+}
+
+// sameSyntax reports whether other reads route segments the way r does.
+func (r *RadixTree) sameSyntax(other *RadixTree) bool {
+	return r.opts.ParamPrefix == other.opts.ParamPrefix && r.opts.ParamSuffix == other.opts.ParamSuffix &&
+		r.opts.WildcardPrefix == other.opts.WildcardPrefix && r.opts.WildcardSuffix == other.opts.WildcardSuffix
+}
+
+// takes reports whether the routes of src can be moved onto n without
+// clashing with the ones n holds.
+func (n *Node) takes(src *Node) bool {
+	if src.handler != nil && (n.handler != nil || n.optionalChild != nil) {
+		return false
+	}
+	for _, mh := range src.methods {
+		if n.methodHandler(mh.method) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *Node) hasChildren() bool {
+	return len(n.static_children) > 0 || len(n.params_children) > 0 || len(n.wildcard_children) > 0
+}
+
+// Mount adds every route of sub below prefix, so a route at "/users/:id" in
+// sub mounted at ["api", "v1"] becomes "/api/v1/users/:id". sub's nodes are
+// grafted keeping their constraints, optional params and fallthrough flags,
+// and checked against r's options and normalizer as Add would check them.
+// If a route of sub clashes with one already registered, or breaks one of
+// those rules, nothing is mounted and the error names the first problem.
+// Both trees must use the same segment markers.
+func (r *RadixTree) Mount(prefix []string, sub *RadixTree) error {
+	if !r.sameSyntax(sub) {
+		return fmt.Errorf("mounted tree uses different segment markers")
+	}
+	return r.update(func(root *Node) error {
+		snapshot := sub.root.Load()
+		if snapshot.nodeSize == 0 {
+			return nil
+		}
+		trail, err := r.addRoute(root, prefix, func(*Node) error { return nil }, nil)
+		if err != nil {
+			return err
+		}
+		// addRoute counted the mount point as a route; take it back.
+		for _, n := range trail {
+			n.nodeSize--
+		}
+
+		mounted := cloneNode(snapshot, r.version)
+		r.stampAll(mounted)
+		added, err := r.graft(trail, mounted, nil)
+		if err != nil {
+			return err
+		}
+		for _, n := range trail[:len(trail)-1] {
+			n.nodeSize += added
+		}
+
+		r.recordRoutes(append(trail[:len(trail)-1:len(trail)-1], snapshot), slices.Clone(prefix))
+		return nil
+	})
+}
+
+// graft moves the routes and children of src into dst, the node at the end
+// of trail, merging nodes that both hold, and returns how many routes dst
+// gained. The nodes of trail must be owned by the write in progress, and
+// src must be a copy it made. A route of src clashing with one of dst is an
+// error, unless clashes is non-nil: the route is then dropped from src and
+// its path appended to clashes.
+func (r *RadixTree) graft(trail []*Node, src *Node, clashes *[][]string) (uint32, error) {
+	dst := trail[len(trail)-1]
+	if dst.nodeType == Wildcard && src.hasChildren() {
+		return 0, fmt.Errorf("%w: %q", ErrWildcardNotLast, pattern(trail))
+	}
+	var added uint32
+	if src.handler != nil {
+		if err := assignHandler(src.handler)(dst); err != nil {
+			if clashes == nil {
+				return 0, fmt.Errorf("%w: %q", ErrHandlerExists, pattern(trail))
+			}
+			*clashes = append(*clashes, r.segments(trail[1:]))
+			src.handler, src.isFallthrough, src.meta = nil, false, nil
+		} else {
+			dst.isFallthrough = src.isFallthrough
+			dst.meta = src.meta
+			added++
+		}
+	}
+	kept := src.methods[:0]
+	for _, mh := range src.methods {
+		if err := assignMethod(mh.method, mh.handler)(dst); err != nil {
+			if clashes == nil {
+				return 0, fmt.Errorf("%w: %s %q", ErrHandlerExists, mh.method, pattern(trail))
+			}
+			*clashes = append(*clashes, r.segments(trail[1:]))
+			continue
+		}
+		kept = append(kept, mh)
+		added++
+	}
+	src.methods = kept
+	if added > 0 {
+		dst.seq = max(dst.seq, src.seq)
+	}
+
+	if opt := src.optionalChild; opt != nil && (dst.handler != nil || len(dst.methods) > 0 || dst.optionalChild != nil) {
+		if clashes == nil {
+			return 0, fmt.Errorf("%w: %q", ErrHandlerExists, pattern(trail))
+		}
+		*clashes = append(*clashes, r.segments(extend(trail[1:], opt)))
+		opt.handler, opt.isFallthrough, opt.meta, opt.defaultValues = nil, false, nil, nil
+		opt.nodeSize--
+		src.optionalChild = nil
+	}
+
+	for _, child := range src.sortedChildren() {
+		n, err := r.graftChild(trail, child, clashes)
+		if err != nil {
+			return 0, err
+		}
+		added += n
+	}
+
+	if opt := src.optionalChild; opt != nil {
+		if node := dst.params_children[opt.paramName]; node != nil {
+			node.defaultValues = opt.defaultValues
+			dst.optionalChild = node
+		}
+	}
+	dst.nodeSize += added
+	return added, nil
+}
+
+// graftChild grafts child, a child of src, below dst, the node at the end
+// of trail: into the matching child of dst, or into a new one. New nodes
+// are checked against the tree's options as addRoute checks them, and
+// static segments are normalized.
+func (r *RadixTree) graftChild(trail []*Node, child *Node, clashes *[][]string) (uint32, error) {
+	dst := trail[len(trail)-1]
+	if r.opts.StrictNames && child.nodeType != Static && child.paramName == "" {
+		return 0, fmt.Errorf("%w: %q", ErrEmptyName, child.path)
+	}
+	if r.opts.UniqueParams && child.nodeType != Static && child.paramName != "" && capturesParam(trail, child.paramName) {
+		return 0, fmt.Errorf("%w: %q in %v", ErrDuplicateParam, child.paramName, append(r.segments(trail[1:]), r.label(child)))
+	}
+
+	var existing *Node
+	switch child.nodeType {
+	case Static:
+		existing = dst.static_children[r.normalize(child.path)]
+	case ParamNode:
+		existing = dst.params_children[child.paramName]
+		if existing != nil && child.constraint != nil &&
+			(existing.constraint == nil || existing.constraint.String() != child.constraint.String()) {
+			return 0, fmt.Errorf("%w: %q", ErrConstraintConflict, childPattern(trail, existing.path))
+		}
+	case Wildcard:
+		// Wildcards with another minimum length are separate routes, as
+		// AddWildcard registers them, and a wildcard whose routes clash
+		// with every copy gets a sibling of its own, as Add gives it.
+		if i := slices.IndexFunc(dst.wildcard_children, func(wc *Node) bool {
+			return wc.path == child.path && wc.minSegments == child.minSegments && wc.takes(child)
+		}); i >= 0 {
+			existing = dst.wildcard_children[i]
+		}
+	}
+	if existing != nil {
+		return r.graft(extend(trail, r.ownChild(dst, existing)), child, clashes)
+	}
+	if child.nodeSize == 0 {
+		// Nothing is left to register below child, such as an optional
+		// route dropped as a clash.
+		return 0, nil
+	}
+
+	node := &Node{
+		version:    r.version,
+		nodeType:   child.nodeType,
+		path:       child.path,
+		paramName:  child.paramName,
+		constraint: child.constraint,
+	}
+	switch child.nodeType {
+	case Static:
+		node.path = r.normalize(child.path)
+		if r.opts.StrictChildren && len(dst.params_children) > 0 {
+			return 0, kindConflictError(trail, node.path, Static, dst.sortedChildren()[len(dst.static_keys)])
+		}
+		if dst.static_children == nil {
+			dst.static_children = make(map[string]*Node)
+		}
+		dst.static_children[node.path] = node
+		i, _ := slices.BinarySearch(dst.static_keys, node.path)
+		dst.static_keys = slices.Insert(dst.static_keys, i, node.path)
+	case ParamNode:
+		if r.opts.StrictChildren && len(dst.static_children) > 0 {
+			return 0, kindConflictError(trail, node.path, ParamNode, dst.static_children[dst.static_keys[0]])
+		}
+		if r.opts.StrictWildcards && len(dst.wildcard_children) > 0 {
+			return 0, ambiguityError(trail, node.path, dst.wildcard_children[0])
+		}
+		r.dynamic.Store(true)
+		if dst.params_children == nil {
+			dst.params_children = make(map[string]*Node)
+		}
+		dst.params_children[node.paramName] = node
+		i, _ := slices.BinarySearch(dst.params_keys, node.paramName)
+		dst.params_keys = slices.Insert(dst.params_keys, i, node.paramName)
+	case Wildcard:
+		if r.opts.StrictWildcards {
+			if len(dst.wildcard_children) > 0 {
+				return 0, ambiguityError(trail, node.path, dst.wildcard_children[0])
+			}
+			for _, param := range dst.params_children {
+				return 0, ambiguityError(trail, node.path, param)
+			}
+		}
+		r.dynamic.Store(true)
+		node.isWildcard, node.matchesEmpty, node.minSegments = true, r.matchesEmpty(child.path), child.minSegments
+		dst.wildcard_children = append(dst.wildcard_children, node)
+	}
+	return r.graft(extend(trail, node), child, clashes)
+}
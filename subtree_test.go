@@ -0,0 +1,278 @@
+package radix_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceSubtree(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"api", "v1"}, "v1")
+	tree.Add([]string{"api", "v1", "users"}, "old_users")
+	tree.Add([]string{"api", "v1", "orders"}, "old_orders")
+	tree.Add([]string{"health"}, "health")
+
+	replacement := radix.NewRadixTree()
+	replacement.Add([]string{}, "new_v1")
+	replacement.Add([]string{"users", ":id"}, "new_user")
+
+	err := tree.ReplaceSubtree([]string{"api", "v1"}, replacement)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), tree.Size())
+	assert.Equal(t, tree.Count(), int(tree.Size()))
+
+	assert.Equal(t, []string{"new_v1"}, handlers(tree.Get([]string{"api", "v1"})))
+	assert.Empty(t, tree.Get([]string{"api", "v1", "users"}))
+	assert.Empty(t, tree.Get([]string{"api", "v1", "orders"}))
+	routes := tree.Get([]string{"api", "v1", "users", "42"})
+	assert.Equal(t, []string{"new_user"}, handlers(routes))
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, routes[0].Params)
+	assert.Equal(t, []string{"health"}, handlers(tree.Get([]string{"health"})))
+
+	leaf, _, _ := tree.GetLeaf([]string{"api", "v1", "users", "42"})
+	assert.Equal(t, "/api/v1/users/:id", leaf.Pattern(), "Parent pointers should lead back through the prefix")
+
+	replacement.Add([]string{"later"}, "later")
+	assert.Empty(t, tree.Get([]string{"api", "v1", "later"}), "The graft should be a copy")
+}
+
+func TestReplaceSubtreeEmpty(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"api", "v1", "users"}, "users")
+	tree.Add([]string{"health"}, "health")
+
+	err := tree.ReplaceSubtree([]string{"api", "v1"}, radix.NewRadixTree())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), tree.Size())
+	assert.False(t, tree.HasPrefix([]string{"api"}), "Emptied nodes should be pruned")
+}
+
+func TestReplaceSubtreeParamNode(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.AddOptional([]string{"users", ":id"}, "user", "me")
+
+	replacement := radix.NewRadixTree()
+	replacement.Add([]string{"posts"}, "posts")
+
+	err := tree.ReplaceSubtree([]string{"users", ":id"}, replacement)
+	assert.Nil(t, err)
+	assert.Empty(t, tree.Get([]string{"users"}), "The optional route went with the old handler")
+	routes := tree.Get([]string{"users", "42", "posts"})
+	assert.Equal(t, []string{"posts"}, handlers(routes))
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, routes[0].Params)
+}
+
+func TestReplaceSubtreeErrors(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"files", "*path"}, "files")
+
+	err := tree.ReplaceSubtree([]string{"missing"}, radix.NewRadixTree())
+	assert.ErrorIs(t, err, radix.ErrPathNotFound)
+
+	children := radix.NewRadixTree()
+	children.Add([]string{"meta"}, "meta")
+	err = tree.ReplaceSubtree([]string{"files", "*path"}, children)
+	assert.ErrorIs(t, err, radix.ErrWildcardNotLast)
+
+	braces := radix.NewRadixTreeWithOptions(radix.Options{ParamPrefix: '{', ParamSuffix: '}'})
+	err = tree.ReplaceSubtree([]string{"files"}, braces)
+	assert.Error(t, err)
+	assert.Equal(t, uint32(1), tree.Size())
+}
+
+func TestReplaceSubtreeRoot(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"old"}, "old")
+
+	replacement := radix.NewRadixTree()
+	replacement.Add([]string{"new", ":id"}, "new")
+
+	assert.Nil(t, tree.ReplaceSubtree([]string{}, replacement))
+	assert.Equal(t, uint32(1), tree.Size())
+	assert.Empty(t, tree.Get([]string{"old"}))
+	assert.Len(t, tree.Get([]string{"new", "1"}), 1, "A static-only tree should start matching params")
+}
+
+func TestReplaceSubtreeJournal(t *testing.T) {
+	tree := radix.NewRadixTree().WithJournal()
+	tree.Add([]string{"api", "users"}, "users")
+
+	replacement := radix.NewRadixTree()
+	replacement.Add([]string{"orders"}, "orders")
+	replacement.AddMethod("GET", []string{"orders"}, "get_orders")
+	assert.Nil(t, tree.ReplaceSubtree([]string{"api"}, replacement))
+
+	var buf bytes.Buffer
+	assert.Nil(t, tree.WriteJournal(&buf))
+	replayed, err := radix.Replay(&buf, func(path []string) radix.Handler { return "replayed" })
+	assert.Nil(t, err)
+	assert.Equal(t, tree.Size(), replayed.Size())
+	assert.Len(t, replayed.Get([]string{"api", "orders"}), 2)
+	assert.Empty(t, replayed.Get([]string{"api", "users"}))
+}
+
+func TestReplaceSubtreeChecksOptions(t *testing.T) {
+	replacement := radix.NewRadixTree()
+	replacement.Add([]string{":id"}, "user")
+	replacement.Add([]string{"*rest"}, "rest")
+
+	tree := radix.NewRadixTreeWithOptions(radix.Options{StrictWildcards: true})
+	tree.Add([]string{"users"}, "users")
+	assert.ErrorIs(t, tree.ReplaceSubtree([]string{"users"}, replacement), radix.ErrAmbiguousRoute)
+	assert.Equal(t, []string{"users"}, handlers(tree.Get([]string{"users"})), "A failed replacement should change nothing")
+
+	tree = radix.NewRadixTreeWithOptions(radix.Options{UniqueParams: true})
+	tree.Add([]string{"users", ":id"}, "user")
+	assert.ErrorIs(t, tree.ReplaceSubtree([]string{"users", ":id"}, replacement), radix.ErrDuplicateParam)
+
+	tree = radix.NewRadixTreeWithOptions(radix.Options{StrictChildren: true})
+	tree.Add([]string{"users"}, "users")
+	mixed := radix.NewRadixTree()
+	mixed.Add([]string{"new"}, "new_user")
+	mixed.Add([]string{":id"}, "user")
+	assert.ErrorIs(t, tree.ReplaceSubtree([]string{"users"}, mixed), radix.ErrAmbiguousRoute)
+
+	tree = radix.NewRadixTree()
+	tree.SetNormalizer(strings.ToLower)
+	tree.Add([]string{"users"}, "users")
+	upper := radix.NewRadixTree()
+	upper.Add([]string{"NEW"}, "new_user")
+	assert.Nil(t, tree.ReplaceSubtree([]string{"users"}, upper))
+	assert.Equal(t, []string{"new_user"}, handlers(tree.Get([]string{"users", "new"})))
+	assert.Equal(t, "new", tree.Root().Children()[0].Children()[0].PathName(), "Replaced segments should be normalized")
+}
+
+func TestMount(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"api", "v1", "health"}, "health")
+
+	sub := radix.NewRadixTree()
+	sub.Add([]string{}, "index")
+	sub.Add([]string{"users", ":id"}, "user")
+	sub.AddMethod("GET", []string{"users"}, "list")
+	sub.AddWithConstraints([]string{"orders", ":order"}, "order", map[string]*regexp.Regexp{"order": regexp.MustCompile(`^\d+$`)})
+	sub.Add([]string{"files", "*path"}, "files")
+
+	assert.Nil(t, tree.Mount([]string{"api", "v1"}, sub))
+	assert.Equal(t, uint32(6), tree.Size())
+	assert.Equal(t, tree.Count(), int(tree.Size()))
+
+	routes := tree.Get([]string{"api", "v1", "users", "42"})
+	assert.Equal(t, []string{"user"}, handlers(routes))
+	assert.Equal(t, radix.Params{{Key: "id", Values: []string{"42"}}}, routes[0].Params)
+	assert.Equal(t, []string{"index"}, handlers(tree.Get([]string{"api", "v1"})))
+	assert.Equal(t, []string{"list"}, handlers(tree.GetMethod("GET", []string{"api", "v1", "users"})))
+	assert.Equal(t, []string{"health"}, handlers(tree.Get([]string{"api", "v1", "health"})))
+	assert.Empty(t, tree.Get([]string{"api", "v1", "orders", "abc"}), "Constraints should survive the mount")
+	assert.Len(t, tree.Get([]string{"api", "v1", "orders", "7"}), 1)
+
+	leaf, _, _ := tree.GetLeaf([]string{"api", "v1", "files", "a", "b"})
+	assert.Equal(t, "/api/v1/files/*path", leaf.Pattern())
+
+	sub.Add([]string{"later"}, "later")
+	assert.Empty(t, tree.Get([]string{"api", "v1", "later"}), "The mounted routes should be a copy")
+}
+
+func TestMountConflict(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"api", "users", ":id"}, "existing")
+
+	sub := radix.NewRadixTree()
+	sub.Add([]string{"orders"}, "orders")
+	sub.Add([]string{"users", ":id"}, "user")
+
+	err := tree.Mount([]string{"api"}, sub)
+	assert.ErrorIs(t, err, radix.ErrHandlerExists)
+	assert.ErrorContains(t, err, `"/api/users/:id"`)
+	assert.Equal(t, uint32(1), tree.Size())
+	assert.Empty(t, tree.Get([]string{"api", "orders"}), "A failed mount should change nothing")
+
+	wildcard := radix.NewRadixTree()
+	wildcard.Add([]string{"files", "*path"}, "files")
+	err = wildcard.Mount([]string{"files", "*path"}, sub)
+	assert.ErrorIs(t, err, radix.ErrWildcardNotLast)
+}
+
+func TestMountEmptyAndRoot(t *testing.T) {
+	tree := radix.NewRadixTree()
+	assert.Nil(t, tree.Mount([]string{"api"}, radix.NewRadixTree()))
+	assert.False(t, tree.HasPrefix([]string{"api"}), "Mounting an empty tree should add nothing")
+
+	sub := radix.NewRadixTree()
+	sub.Add([]string{"users", ":id"}, "user")
+	assert.Nil(t, tree.Mount([]string{}, sub))
+	assert.Equal(t, uint32(1), tree.Size())
+	assert.Len(t, tree.Get([]string{"users", "1"}), 1)
+}
+
+func TestMountDuplicateWildcards(t *testing.T) {
+	sub := radix.NewRadixTree()
+	sub.Add([]string{"a", "*x"}, "h1")
+	sub.Add([]string{"a", "*x"}, "h2")
+
+	tree := radix.NewRadixTree()
+	assert.Nil(t, tree.Mount([]string{"p"}, sub), "Wildcard siblings Add accepts should mount")
+	assert.Equal(t, uint32(2), tree.Size())
+	assert.Equal(t, []string{"h1", "h2"}, handlers(tree.Get([]string{"p", "a", "b"})))
+
+	assert.Nil(t, tree.Mount([]string{"p"}, sub), "Clashing wildcards should be grafted as siblings")
+	assert.Equal(t, []string{"h1", "h2", "h1", "h2"}, handlers(tree.Get([]string{"p", "a", "b"})))
+
+	replaced := radix.NewRadixTree()
+	replaced.Add([]string{"p"}, "p")
+	assert.Nil(t, replaced.ReplaceSubtree([]string{"p"}, sub))
+	assert.Equal(t, uint32(2), replaced.Size())
+	assert.Equal(t, []string{"h1", "h2"}, handlers(replaced.Get([]string{"p", "a", "b"})))
+}
+
+func TestMountJournal(t *testing.T) {
+	tree := radix.NewRadixTree().WithJournal()
+
+	sub := radix.NewRadixTree()
+	sub.Add([]string{"users", ":id"}, "user")
+	assert.Nil(t, tree.Mount([]string{"api"}, sub))
+
+	var buf bytes.Buffer
+	assert.Nil(t, tree.WriteJournal(&buf))
+	replayed, err := radix.Replay(&buf, func(path []string) radix.Handler { return "replayed" })
+	assert.Nil(t, err)
+	assert.Len(t, replayed.Get([]string{"api", "users", "1"}), 1)
+}
+
+func TestMountChecksOptions(t *testing.T) {
+	sub := radix.NewRadixTree()
+	sub.Add([]string{"users", ":id"}, "user")
+	sub.Add([]string{"users", "new"}, "new_user")
+
+	strict := radix.NewRadixTreeWithOptions(radix.Options{StrictChildren: true})
+	assert.ErrorIs(t, strict.Mount([]string{"api"}, sub), radix.ErrAmbiguousRoute)
+	assert.Zero(t, strict.Size())
+	_, err := strict.Merge(sub)
+	assert.ErrorIs(t, err, radix.ErrAmbiguousRoute)
+
+	wildcards := radix.NewRadixTreeWithOptions(radix.Options{StrictWildcards: true})
+	wildcards.Add([]string{"api", "users", "*rest"}, "rest")
+	assert.ErrorIs(t, wildcards.Mount([]string{"api"}, sub), radix.ErrAmbiguousRoute)
+
+	unique := radix.NewRadixTreeWithOptions(radix.Options{UniqueParams: true})
+	assert.ErrorIs(t, unique.Mount([]string{":id"}, sub), radix.ErrDuplicateParam)
+
+	unnamed := radix.NewRadixTree()
+	unnamed.Add([]string{":"}, "unnamed")
+	names := radix.NewRadixTreeWithOptions(radix.Options{StrictNames: true})
+	assert.ErrorIs(t, names.Mount([]string{"api"}, unnamed), radix.ErrEmptyName)
+
+	folded := radix.NewRadixTree()
+	folded.SetNormalizer(strings.ToLower)
+	folded.Add([]string{"api", "users"}, "users")
+	mixed := radix.NewRadixTree()
+	mixed.Add([]string{"Users", ":id"}, "user")
+	assert.Nil(t, folded.Mount([]string{"api"}, mixed))
+	assert.Equal(t, []string{"users", "user"}, append(handlers(folded.Get([]string{"API", "USERS"})), handlers(folded.Get([]string{"api", "users", "1"}))...))
+	assert.Len(t, folded.Root().Children()[0].Children(), 1, "Mounted segments should be normalized into existing nodes")
+}
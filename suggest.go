@@ -0,0 +1,72 @@
+package radix
+
+import "slices"
+
+// maxSuggestions bounds the patterns returned by Suggest.
+const maxSuggestions = 5
+
+// Suggest returns route patterns close to path for a path no route matches,
+// such as "/users" for "/usrs", to help build friendlier not-found
+// responses. It descends from the root as LongestPrefix does and, where path
+// stops matching, returns the patterns of the static children there, closest
+// to the unmatched segment first, at most five of them. It returns nil when
+// a route matches path.
+func (r *RadixTree) Suggest(path []string) [][]string {
+	if r.Get(path) != nil {
+		return nil
+	}
+	node := r.root.Load()
+	var buf trailBuffer
+	trail := buf.start(node)
+	segment := ""
+	for _, s := range path {
+		child := r.staticChild(node, s)
+		if child == nil {
+			child = node.paramChild(s)
+		}
+		if child == nil {
+			segment = r.normalize(s)
+			break
+		}
+		node = child
+		trail = append(trail, node)
+	}
+
+	keys := make([]string, 0, len(node.static_keys))
+	for _, key := range node.static_keys {
+		if node.static_children[key].nodeSize > 0 {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortStableFunc(keys, func(a, b string) int {
+		return editDistance(a, segment) - editDistance(b, segment)
+	})
+
+	var suggestions [][]string
+	for _, key := range keys[:min(len(keys), maxSuggestions)] {
+		suggestions = append(suggestions, wrap(extend(trail, node.static_children[key])).Path())
+	}
+	return suggestions
+}
+
+// editDistance returns the Levenshtein distance between a and b, counted in
+// runes.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	row := make([]int, len(t)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			prev, row[j] = row[j], min(row[j]+1, row[j-1]+1, prev+cost)
+		}
+	}
+	return row[len(t)]
+}
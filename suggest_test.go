@@ -0,0 +1,35 @@
+package radix_test
+
+import (
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggest(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.Add([]string{"users"}, "users")
+	tree.Add([]string{"posts"}, "posts")
+	tree.Add([]string{"users", ":id", "orders"}, "orders")
+	tree.Add([]string{"users", ":id", "profile"}, "profile")
+
+	assert.Equal(t, [][]string{{"users"}, {"posts"}}, tree.Suggest([]string{"usrs"}))
+	assert.Equal(t, [][]string{{"users", ":id", "orders"}, {"users", ":id", "profile"}},
+		tree.Suggest([]string{"users", "42", "ordrs"}))
+	assert.Equal(t, [][]string{{"users", ":id", "profile"}, {"users", ":id", "orders"}},
+		tree.Suggest([]string{"users", "42", "profil", "extra"}))
+	assert.Nil(t, tree.Suggest([]string{"users"}), "A matched path should have no suggestions")
+	assert.Nil(t, tree.Suggest([]string{"users", "42", "orders"}))
+}
+
+func TestSuggestLimit(t *testing.T) {
+	tree := radix.NewRadixTree()
+	for _, name := range []string{"a1", "a2", "a3", "a4", "a5", "a6", "b"} {
+		tree.Add([]string{name}, name)
+	}
+
+	suggestions := tree.Suggest([]string{"bb"})
+	assert.Len(t, suggestions, 5)
+	assert.Equal(t, []string{"b"}, suggestions[0])
+}
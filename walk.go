@@ -0,0 +1,120 @@
+package radix
+
+import "slices"
+
+// Walk traverses the tree depth-first and calls fn for every node that holds
+// a handler, passing the full route segments leading to it. Param and
+// wildcard segments are reported with their ':' and '*' markers, and static
+// segments that would read as one are escaped with a leading '\'. Handlers
+// registered with AddMethod are visited after the node's method-less handler.
+// The traversal stops as soon as fn returns false.
+func (r *RadixTree) Walk(fn func(path []string, handler Handler) bool) {
+	r.walk(func(path []string, method string, handler Handler) bool {
+		return fn(path, handler)
+	})
+}
+
+// Count returns the number of registered routes by traversing the whole
+// tree. Unlike Size, it does not rely on the per-node counters.
+func (r *RadixTree) Count() int {
+	count := 0
+	r.walk(func(path []string, method string, handler Handler) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+func (r *RadixTree) walk(fn func(path []string, method string, handler Handler) bool) {
+	walkNode(r.root.Load(), nil, r.label, false, fn)
+}
+
+// WalkSorted traverses the tree like Walk, but visits children in a stable
+// order: static children by segment, params by name, then wildcards in
+// registration order. Two trees holding the same routes are enumerated the
+// same way.
+func (r *RadixTree) WalkSorted(fn func(path []string, handler Handler) bool) {
+	walkNode(r.root.Load(), nil, r.label, true, func(path []string, method string, handler Handler) bool {
+		return fn(path, handler)
+	})
+}
+
+// Walk traverses the subtree rooted at the node like RadixTree.Walk, passing
+// route segments relative to the node, labeled as PathName reports them. The
+// node itself is visited first, with an empty path, if it holds a handler.
+func (nw *NodeWrapper) Walk(fn func(relPath []string, handler Handler) bool) {
+	walkNode(nw.node(), nil, func(n *Node) string { return n.path }, false, func(path []string, method string, handler Handler) bool {
+		return fn(path, handler)
+	})
+}
+
+// label returns the route segment registering n.
+func (r *RadixTree) label(n *Node) string {
+	if n.nodeType == Static {
+		return r.escape(n.path)
+	}
+	return n.path
+}
+
+func walkNode(node *Node, path []string, label func(*Node) string, sorted bool, fn func(path []string, method string, handler Handler) bool) bool {
+	if node.handler != nil || len(node.methods) > 0 {
+		segments := make([]string, len(path))
+		copy(segments, path)
+		if node.handler != nil && !fn(segments, "", node.handler) {
+			return false
+		}
+		for _, mh := range node.methods {
+			if !fn(segments, mh.method, mh.handler) {
+				return false
+			}
+		}
+	}
+
+	if sorted {
+		for _, child := range node.sortedChildren() {
+			if !walkNode(child, append(path, label(child)), label, sorted, fn) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, child := range node.static_children {
+		if !walkNode(child, append(path, label(child)), label, sorted, fn) {
+			return false
+		}
+	}
+	for _, child := range node.params_children {
+		if !walkNode(child, append(path, label(child)), label, sorted, fn) {
+			return false
+		}
+	}
+	for _, child := range node.wildcard_children {
+		if !walkNode(child, append(path, label(child)), label, sorted, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// RoutesByTopLevel groups the patterns of all registered routes by their
+// first segment, labeled as Walk reports it, so param and wildcard segments
+// keep their markers. A route at the root is keyed by "". Each group lists
+// its patterns in WalkSorted order, once per node however many handlers
+// the node holds.
+func (r *RadixTree) RoutesByTopLevel() map[string][][]string {
+	groups := map[string][][]string{}
+	var last []string
+	walkNode(r.root.Load(), nil, r.label, true, func(path []string, method string, handler Handler) bool {
+		if last != nil && slices.Equal(last, path) {
+			return true
+		}
+		last = path
+		key := ""
+		if len(path) > 0 {
+			key = path[0]
+		}
+		groups[key] = append(groups[key], path)
+		return true
+	})
+	return groups
+}
@@ -0,0 +1,161 @@
+package radix_test
+
+import (
+	"strings"
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{}, "root")
+	tree.Add([]string{"users"}, "users")
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"api", "v1"}, "api_v1")
+	tree.Add([]string{"files", "*filepath"}, "files")
+
+	expected := map[string]string{
+		"":                "root",
+		"users":           "users",
+		"users/:id":       "user_show",
+		"api/v1":          "api_v1",
+		"files/*filepath": "files",
+	}
+
+	visited := make(map[string]string)
+	tree.Walk(func(path []string, handler radix.Handler) bool {
+		visited[strings.Join(path, "/")] = handler.(string)
+		return true
+	})
+
+	assert.Equal(t, expected, visited)
+}
+
+func TestWalkStopsEarly(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"a"}, "a")
+	tree.Add([]string{"b"}, "b")
+	tree.Add([]string{"c"}, "c")
+
+	calls := 0
+	tree.Walk(func(path []string, handler radix.Handler) bool {
+		calls++
+		return false
+	})
+
+	assert.Equal(t, 1, calls, "Walk should stop after fn returns false")
+}
+
+func TestWalkEmptyTree(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	calls := 0
+	tree.Walk(func(path []string, handler radix.Handler) bool {
+		calls++
+		return true
+	})
+
+	assert.Zero(t, calls, "Empty tree should not visit any route")
+}
+
+func TestCount(t *testing.T) {
+	tree := radix.NewRadixTree()
+	assert.Zero(t, tree.Count())
+
+	tree.Add([]string{}, "root")
+	tree.Add([]string{"users"}, "users")
+	tree.Add([]string{"users", ":id", "posts"}, "user_posts")
+	tree.Add([]string{"files", "*filepath"}, "files")
+	tree.Add([]string{"files", "*other"}, "files_other")
+	assert.Equal(t, 5, tree.Count())
+	assert.Equal(t, uint32(tree.Count()), tree.Size())
+
+	tree.Delete([]string{"users", ":id", "posts"})
+	tree.Delete([]string{"files", "*filepath"})
+	assert.Equal(t, 3, tree.Count())
+	assert.Equal(t, uint32(tree.Count()), tree.Size())
+}
+
+func TestNodeWrapperWalk(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"admin", "users", ":id"}, "admin_user")
+	tree.Add([]string{"admin", "*rest"}, "admin_rest")
+	tree.Add([]string{"users"}, "users")
+	tree.Add([]string{"admin"}, "admin")
+
+	admin, _, found := tree.GetLeaf([]string{"admin"})
+	assert.True(t, found)
+
+	visited := make(map[string]string)
+	admin.Walk(func(relPath []string, handler radix.Handler) bool {
+		visited[strings.Join(relPath, "/")] = handler.(string)
+		return true
+	})
+	assert.Equal(t, map[string]string{
+		"":          "admin",
+		"users/:id": "admin_user",
+		"*rest":     "admin_rest",
+	}, visited)
+
+	calls := 0
+	tree.Root().Walk(func(relPath []string, handler radix.Handler) bool {
+		calls++
+		return false
+	})
+	assert.Equal(t, 1, calls, "Walk should stop after fn returns false")
+}
+
+func TestWalkSorted(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.Add([]string{"users", "*rest"}, "user_rest")
+	tree.Add([]string{"users", ":name"}, "user_name")
+	tree.Add([]string{"users", ":id"}, "user_show")
+	tree.Add([]string{"users", "new"}, "user_new")
+	tree.Add([]string{"admin"}, "admin")
+	tree.Add([]string{}, "root")
+	tree.Add([]string{"users"}, "users")
+
+	expected := []string{"", "admin", "users", "users/new", "users/:id", "users/:name", "users/*rest"}
+	for i := 0; i < 10; i++ {
+		var visited []string
+		tree.WalkSorted(func(path []string, handler radix.Handler) bool {
+			visited = append(visited, strings.Join(path, "/"))
+			return true
+		})
+		assert.Equal(t, expected, visited)
+	}
+
+	calls := 0
+	tree.WalkSorted(func(path []string, handler radix.Handler) bool {
+		calls++
+		return calls < 3
+	})
+	assert.Equal(t, 3, calls, "WalkSorted should stop after fn returns false")
+}
+
+func TestRoutesByTopLevel(t *testing.T) {
+	tree := radix.NewRadixTree()
+	tree.SetRoot("root")
+	tree.Add([]string{"api", "users"}, "users")
+	tree.Add([]string{"api", "users", ":id"}, "user")
+	tree.AddMethod("GET", []string{"api", "users"}, "list")
+	tree.Add([]string{":lang", "docs"}, "docs")
+	tree.Add([]string{"*rest"}, "rest")
+	tree.Add([]string{`\:443`}, "port")
+
+	assert.Equal(t, map[string][][]string{
+		"":      {{}},
+		"api":   {{"api", "users"}, {"api", "users", ":id"}},
+		":lang": {{":lang", "docs"}},
+		"*rest": {{"*rest"}},
+		`\:443`: {{`\:443`}},
+	}, tree.RoutesByTopLevel())
+
+	assert.Empty(t, radix.NewRadixTree().RoutesByTopLevel())
+}
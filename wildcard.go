@@ -0,0 +1,66 @@
+package radix
+
+import "fmt"
+
+// AddWildcard registers handler like Add at path, whose last segment must be
+// a wildcard, and only lets the route match when the wildcard captures at
+// least minSegments segments. Shorter paths fall through to lower-priority
+// matches. Registering the same wildcard again with a different minimum
+// adds a sibling route rather than changing the existing one.
+func (r *RadixTree) AddWildcard(path []string, handler Handler, minSegments int) (*NodeWrapper, error) {
+	if err := r.checkMinSegments(path, minSegments); err != nil {
+		return nil, err
+	}
+	var trail []*Node
+	err := r.update(func(root *Node) (err error) {
+		if trail, err = r.addRoute(root, path, withMinSegments(assignHandler(handler), minSegments), nil); err != nil {
+			return err
+		}
+		r.record(journalEntry{Op: journalAdd, Path: path, MinSegments: minSegments})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.live(trail), nil
+}
+
+// checkMinSegments reports whether path can take a minimum wildcard length.
+func (r *RadixTree) checkMinSegments(path []string, minSegments int) error {
+	if len(path) == 0 {
+		return fmt.Errorf("route needs a trailing wildcard segment")
+	}
+	if nodeType, _ := r.classify(path[len(path)-1]); nodeType != Wildcard {
+		return fmt.Errorf("only a trailing wildcard segment can have a minimum length: %q", path[len(path)-1])
+	}
+	if minSegments < 0 {
+		return fmt.Errorf("invalid minimum wildcard length %d", minSegments)
+	}
+	return nil
+}
+
+// withMinSegments wraps assign to also set the wildcard's minimum length,
+// failing on a wildcard registered with another one.
+func withMinSegments(assign func(*Node) error, minSegments int) func(*Node) error {
+	return func(node *Node) error {
+		if (node.handler != nil || len(node.methods) > 0) && node.minSegments != minSegments {
+			return fmt.Errorf("%w: %q", ErrHandlerExists, node.path)
+		}
+		if err := assign(node); err != nil {
+			return err
+		}
+		node.minSegments = minSegments
+		return nil
+	}
+}
+
+// minCapture returns the fewest segments the wildcard n matches.
+func (n *Node) minCapture() int {
+	switch {
+	case n.minSegments > 0:
+		return n.minSegments
+	case n.matchesEmpty:
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,76 @@
+package radix_test
+
+import (
+	"testing"
+
+	radix "github.com/saeedsamimi/router-radix-tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddWildcardMinSegments(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	_, err := tree.AddWildcard([]string{"files", "*path"}, "files", 2)
+	assert.Nil(t, err)
+
+	assert.Empty(t, tree.Get([]string{"files", "a"}))
+
+	routes := tree.Get([]string{"files", "a", "b"})
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "files", routes[0].Handler.(string))
+	assert.Equal(t, radix.Params{{Key: "path", Values: []string{"a", "b"}}}, routes[0].Params)
+}
+
+func TestAddWildcardFallsThrough(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.AddWildcard([]string{"files", "*path"}, "nested", 2)
+	_, err := tree.AddWildcard([]string{"files", "*path"}, "any", 0)
+	assert.Nil(t, err, "A different minimum should add a sibling")
+
+	routes := tree.Get([]string{"files", "a"})
+	assert.Equal(t, []string{"any"}, handlers(routes))
+	routes = tree.Get([]string{"files", "a", "b"})
+	assert.Equal(t, []string{"nested", "any"}, handlers(routes))
+	assert.Equal(t, uint32(2), tree.Size())
+}
+
+func TestAddWildcardZeroSegmentMarker(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.AddWildcard([]string{"files", "**path"}, "files", 1)
+	assert.Empty(t, tree.Get([]string{"files"}), "The minimum overrides the zero-segment marker")
+	assert.Len(t, tree.Get([]string{"files", "a"}), 1)
+}
+
+func TestAddWildcardRequiresTrailingWildcard(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	_, err := tree.AddWildcard([]string{"files", ":name"}, "files", 2)
+	assert.Error(t, err)
+	_, err = tree.AddWildcard([]string{}, "files", 2)
+	assert.Error(t, err)
+	_, err = tree.AddWildcard([]string{"files", "*path"}, "files", -1)
+	assert.Error(t, err)
+	assert.Equal(t, uint32(0), tree.Size())
+}
+
+func TestAddWildcardUnreachable(t *testing.T) {
+	tree := radix.NewRadixTree()
+
+	tree.AddWildcard([]string{"files", "*path"}, "nested", 2)
+	tree.Add([]string{"files", "*rest"}, "any")
+	assert.Empty(t, tree.UnreachableRoutes(), "A shorter minimum is not shadowed")
+
+	shadowed := radix.NewRadixTree()
+	shadowed.Add([]string{"files", "*rest"}, "any")
+	shadowed.AddWildcard([]string{"files", "*path"}, "nested", 2)
+	assert.Equal(t, [][]string{{"files", "*path"}}, shadowed.UnreachableRoutes())
+
+	_, err := tree.Add([]string{"files", "x"}, "file")
+	assert.Nil(t, err)
+	assert.Equal(t, [][2][]string{
+		{{"files", "x"}, {"files", "*rest"}},
+		{{"files", "*path"}, {"files", "*rest"}},
+	}, tree.FindConflicts(), "A single segment does not reach the two-segment wildcard")
+}